@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	authenticationmodels "github.com/RobsonDevCode/deepscan/internal/clients/models/githubAuthentication"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "manage the github login used to list repositories and look up advisories",
+	Long: `manage the github login used to list repositories and look up advisories.
+
+		   With github_app_settings configured the app is used instead of a personal login,
+		   its tokens are minted on demand so there is nothing to log in or out of.`,
+}
+
+var authLoginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "sign in to github, replacing any stored token",
+	Args:  cobra.NoArgs,
+	RunE:  runAuthLogin,
+}
+
+var authStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "show who deepscan is signed in to github as, the token scopes and the rate limit left",
+	Args:  cobra.NoArgs,
+	RunE:  runAuthStatus,
+}
+
+var authLogoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "forget the stored github token",
+	Args:  cobra.NoArgs,
+	RunE:  runAuthLogout,
+}
+
+func runAuthLogin(cmd *cobra.Command, args []string) error {
+	status, err := githubAuthManager.Login(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	displayAuthStatus(status)
+	return nil
+}
+
+func runAuthStatus(cmd *cobra.Command, args []string) error {
+	status, err := githubAuthManager.Status(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	displayAuthStatus(status)
+	return nil
+}
+
+func runAuthLogout(cmd *cobra.Command, args []string) error {
+	if err := githubAuthManager.Logout(); err != nil {
+		return err
+	}
+
+	fmt.Print(color.GreenString("\n Logged out of github, revoke the token under github.com/settings/applications to stop it working entirely\n"))
+	return nil
+}
+
+func displayAuthStatus(status authenticationmodels.AuthStatus) {
+	if !status.LoggedIn {
+		fmt.Print(color.YellowString("\n Not logged in to github, run 'deepscan auth login'\n"))
+		return
+	}
+
+	if !status.Valid {
+		fmt.Print(color.RedString("\n The stored github token was revoked or has expired, run 'deepscan auth login'\n"))
+		return
+	}
+
+	user := status.TokenInfo.Login
+	if user == "" {
+		user = "an installation" //app tokens arent a user
+	}
+	fmt.Print(color.GreenString("\n Logged in to github as %s using the %s\n", user, status.Method))
+
+	scopes := "not reported for this token type"
+	if len(status.TokenInfo.Scopes) > 0 {
+		scopes = strings.Join(status.TokenInfo.Scopes, ", ")
+	}
+	fmt.Printf(" Scopes: %s\n", scopes)
+
+	rateLimit := status.TokenInfo.RateLimit
+	fmt.Printf(" Rate limit: %d of %d remaining, resets at %s\n", rateLimit.Remaining, rateLimit.Limit,
+		time.Unix(rateLimit.Reset, 0).Format("15:04"))
+}
+
+func init() {
+	authCmd.AddCommand(authLoginCmd)
+	authCmd.AddCommand(authStatusCmd)
+	authCmd.AddCommand(authLogoutCmd)
+	rootCmd.AddCommand(authCmd)
+}
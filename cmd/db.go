@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "manage the local advisory database used by 'scan --offline'",
+	Long: `manage the local advisory database used by 'scan --offline'.
+
+		   Import an osv zip or github advisory-database checkout on a connected machine,
+		   export it as a bundle and import the bundle inside networks without internet access.`,
+}
+
+var dbImportCmd = &cobra.Command{
+	Use:   "import [osv-zip-or-ghsa-repo-dir-or-bundle]",
+	Short: "build the local advisory database from an osv zip, a github advisory-database checkout or an exported bundle",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runDbImport,
+}
+
+var dbExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "export the local advisory database as a bundle for transfer into offline networks",
+	Args:  cobra.NoArgs,
+	RunE:  runDbExport,
+}
+
+func runDbImport(cmd *cobra.Command, args []string) error {
+	database, err := advisoryDatabaseService.Import(args[0], cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(color.GreenString("\n Advisory database built %s with %d advisories\n",
+		database.BuildDate.Format("2006-01-02 15:04"), len(database.Vulnerabilities)))
+	return nil
+}
+
+func runDbExport(cmd *cobra.Command, args []string) error {
+	outPath, _ := cmd.Flags().GetString("out")
+
+	savedTo, err := advisoryDatabaseService.Export(outPath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(color.GreenString("\n Advisory database bundle saved to: %s\n", savedTo))
+	return nil
+}
+
+func init() {
+	dbExportCmd.Flags().StringP("out", "o", "", "File to write the bundle to, defaults to the export folder")
+
+	dbCmd.AddCommand(dbImportCmd)
+	dbCmd.AddCommand(dbExportCmd)
+	rootCmd.AddCommand(dbCmd)
+}
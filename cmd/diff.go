@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+
+	tablewriterservice "github.com/RobsonDevCode/deepscan/internal/cmdLineWriters/tablewriter"
+	advisorysources "github.com/RobsonDevCode/deepscan/internal/constants/advisorySources"
+	"github.com/RobsonDevCode/deepscan/internal/extensions"
+	scannerselectionservice "github.com/RobsonDevCode/deepscan/internal/services/scannerSelectionService"
+	"github.com/spf13/cobra"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [repository-path-or-url]",
+	Short: "compare vulnerabilities between two refs of a repository",
+	Long: `compare vulnerabilities between two refs of a repository.
+
+		   Scans the repository at --base and at --head and lists the findings each project gains, loses and keeps.
+		   With no argument the repository in the current directory is used, a clone url scans the remote instead.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDiff,
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	source := "."
+	if len(args) == 1 {
+		source = args[0]
+	}
+
+	base, _ := cmd.Flags().GetString("base")
+	head, _ := cmd.Flags().GetString("head")
+	failOn, _ := cmd.Flags().GetString("fail-on")
+	if failOn != "none" && !extensions.IsSeverity(failOn) {
+		return fmt.Errorf("unknown severity %s, use low, medium, high, critical or none", failOn)
+	}
+
+	diffs, err := scanDiffService.Diff(source, base, head, scannerselectionservice.GetScanOptions(cmd), cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	tablewriterservice.DisplayDiffTable(diffs)
+
+	if failOn == "none" {
+		return nil
+	}
+
+	introduced := 0
+	for _, diff := range diffs {
+		for _, finding := range diff.Added {
+			if extensions.MeetsSeverity(finding, failOn) {
+				introduced++
+			}
+		}
+	}
+
+	//the tables have already been printed so usage would just bury them
+	if introduced > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%s introduces %d findings at or above %s", head, introduced, failOn)
+	}
+
+	return nil
+}
+
+func init() {
+	diffCmd.Flags().String("base", "", "Branch, tag or commit to compare against e.g. main")
+	diffCmd.Flags().String("head", "", "Branch, tag or commit with the changes e.g. feature/upgrade-packages")
+	diffCmd.Flags().String("fail-on", "high", "Exit non-zero when head introduces findings at or above this severity, low, medium, high, critical or none")
+	diffCmd.Flags().Bool("no-cache", false, "Rescan every project even if its manifest hasnt changed since the last run")
+	diffCmd.Flags().String("source", advisorysources.Github, "Advisory database to check packages against e.g. github, osv or both")
+	diffCmd.Flags().String("github-api", advisorysources.Rest, "Github api used for advisory lookups, rest or graphql(batches large lockfiles into one request)")
+	diffCmd.Flags().Bool("include-withdrawn", false, "Report advisories that have since been withdrawn")
+	diffCmd.Flags().Bool("offline", false, "Resolve findings only from the local advisory database imported with 'deepscan db import'")
+	diffCmd.MarkFlagRequired("base")
+	diffCmd.MarkFlagRequired("head")
+
+	rootCmd.AddCommand(diffCmd)
+}
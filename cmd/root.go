@@ -6,12 +6,19 @@ package cmd
 import (
 	"os"
 
+	advisorydatabaseservice "github.com/RobsonDevCode/deepscan/internal/services/advisoryDatabaseService"
+	gitubauthenticationservice "github.com/RobsonDevCode/deepscan/internal/services/gitubAuthenticationService"
+	scandiffservice "github.com/RobsonDevCode/deepscan/internal/services/scanDiffService"
 	scannerselectionservice "github.com/RobsonDevCode/deepscan/internal/services/scannerSelectionService"
 	"github.com/spf13/cobra"
 )
 
 var (
 	scannerSelectionService scannerselectionservice.ScanSelection
+	advisoryDatabaseService advisorydatabaseservice.AdvisoryDatabaseService
+	scanDiffService         scandiffservice.ScanDiffService
+	githubAuthManager       gitubauthenticationservice.GithubAuthManagerService
+	githubAuthenticator     gitubauthenticationservice.GithubAuthenticatorService
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -27,12 +34,35 @@ to quickly create a Cobra application.`,
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
 	// Run: func(cmd *cobra.Command, args []string) { },
+
+	// every command can end up authenticating with github, so they all decide up front whether a device code can be entered
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if githubAuthenticator != nil {
+			githubAuthenticator.SetNonInteractive(scannerselectionservice.IsNonInteractive(cmd))
+		}
+	},
 }
 
 func SetScanSelection(s scannerselectionservice.ScanSelection) {
 	scannerSelectionService = s
 }
 
+func SetAdvisoryDatabaseService(a advisorydatabaseservice.AdvisoryDatabaseService) {
+	advisoryDatabaseService = a
+}
+
+func SetScanDiffService(d scandiffservice.ScanDiffService) {
+	scanDiffService = d
+}
+
+func SetGithubAuthManager(g gitubauthenticationservice.GithubAuthManagerService) {
+	githubAuthManager = g
+}
+
+func SetGithubAuthenticator(g gitubauthenticationservice.GithubAuthenticatorService) {
+	githubAuthenticator = g
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
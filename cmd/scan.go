@@ -1,9 +1,18 @@
 package cmd
 
 import (
+	"fmt"
+	"slices"
+	"strings"
+
 	"github.com/RobsonDevCode/deepscan/internal/clients/models"
+	advisorysources "github.com/RobsonDevCode/deepscan/internal/constants/advisorySources"
 	"github.com/RobsonDevCode/deepscan/internal/constants/exportExcelOptions"
+	exportformats "github.com/RobsonDevCode/deepscan/internal/constants/exportFormats"
+	"github.com/RobsonDevCode/deepscan/internal/extensions"
 	excelexportservice "github.com/RobsonDevCode/deepscan/internal/services/excelExportService"
+	jsonexportservice "github.com/RobsonDevCode/deepscan/internal/services/jsonExportService"
+	scannerselectionservice "github.com/RobsonDevCode/deepscan/internal/services/scannerSelectionService"
 	"github.com/spf13/cobra"
 )
 
@@ -23,9 +32,19 @@ var allFlag bool
 func runScan(cmd *cobra.Command, projects []string) error {
 	ctx := cmd.Context()
 
+	//checked before scanning so a typo doesnt waste a long run
+	exportFormats, _ := cmd.Flags().GetStringSlice("export")
+	for i, format := range exportFormats {
+		exportFormats[i] = strings.ToLower(strings.TrimSpace(format))
+		if !slices.Contains(exportformats.Formats, exportFormats[i]) {
+			return fmt.Errorf("cant export as %s, supported formats are %s", format, strings.Join(exportformats.Formats, ", "))
+		}
+	}
+
 	var scannedPackages []models.ScannedPackage
-	if allFlag {
-		scannerResponse, err := scannerSelectionService.ScanAll(ctx)
+	selection, _ := cmd.Flags().GetString("selection")
+	if allFlag || selection != "" {
+		scannerResponse, err := scannerSelectionService.ScanAll(cmd, ctx)
 		if err != nil {
 			return err
 		}
@@ -38,19 +57,50 @@ func runScan(cmd *cobra.Command, projects []string) error {
 		scannedPackages = scannerResponse
 	}
 
-	if len(scannedPackages) == 0 {
-		return nil
+	if err := exportPackages(cmd, scannedPackages, exportFormats, allFlag || selection != ""); err != nil {
+		return err
+	}
+
+	//malware always fails the run, the tables have already been printed so usage would just bury them
+	if malwareCount := extensions.CountMalware(scannedPackages); malwareCount > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("found %d malicious packages", malwareCount)
 	}
 
-	choice, err := excelexportservice.SelectExportPackagesToExcel()
-	if err != nil {
-		return err
+	return nil
+}
+
+// exportPackages --export decides without asking, otherwise interactive runs are asked and non interactive runs skip the export
+func exportPackages(cmd *cobra.Command, scannedPackages []models.ScannedPackage, formats []string, isFullScan bool) error {
+	if len(formats) == 0 {
+		if len(scannedPackages) == 0 || scannerselectionservice.IsNonInteractive(cmd) {
+			return nil
+		}
+
+		choice, err := excelexportservice.SelectExportPackagesToExcel()
+		if err != nil {
+			return err
+		}
+
+		if choice != exportExcelOptions.Yes {
+			return nil
+		}
+		formats = []string{exportformats.Xlsx}
 	}
 
-	if choice == exportExcelOptions.Yes {
-		if err := excelexportservice.ExportPackageTable(scannedPackages, allFlag); err != nil {
+	outDirectory, _ := cmd.Flags().GetString("out")
+	for _, format := range formats {
+		var err error
+		switch format {
+		case exportformats.Xlsx:
+			err = excelexportservice.ExportPackageTable(scannedPackages, isFullScan, outDirectory)
+		case exportformats.Json:
+			err = jsonexportservice.ExportPackages(scannedPackages, isFullScan, outDirectory)
+		}
+		if err != nil {
 			return err
 		}
+		fmt.Println()
 	}
 
 	return nil
@@ -60,6 +110,23 @@ func init() {
 	scanCmd.Flags().StringP("dir", "d", "", "Process from local directory(most effiecent)")
 	scanCmd.Flags().StringP("ssh", "s", "", "Processes using the ssh url for the project repository")
 	scanCmd.Flags().BoolVarP(&allFlag, "all", "a", false, "Scans all projects for package vulnerabilities")
+	scanCmd.Flags().Bool("no-cache", false, "Rescan every project even if its manifest hasnt changed since the last run")
+	scanCmd.Flags().String("source", advisorysources.Github, "Advisory database to check packages against e.g. github, osv or both, dependency-graph reads packages from githubs sbom instead e.g. dependency-graph,osv")
+	scanCmd.Flags().String("github-api", advisorysources.Rest, "Github api used for advisory lookups, rest or graphql(batches large lockfiles into one request)")
+	scanCmd.Flags().Bool("include-withdrawn", false, "Report advisories that have since been withdrawn")
+	scanCmd.Flags().StringSlice("include", nil, "Only scan repositories whose name matches one of these regexes")
+	scanCmd.Flags().StringSlice("exclude", nil, "Skip repositories whose name matches one of these regexes")
+	scanCmd.Flags().StringSlice("topic", nil, "Only scan repositories tagged with one of these topics or labels")
+	scanCmd.Flags().String("selection", "", "Scan the repositories matched by a saved selection e.g. payments-team")
+	scanCmd.Flags().String("save-selection", "", "Save the include, exclude and topic rules or the projects picked in the prompt under this name")
+	scanCmd.Flags().Bool("remote", false, "Read manifests through the github, gitlab or azure devops api instead of cloning, no git or ssh keys needed")
+	scanCmd.Flags().String("ref", "", "Branch, tag or commit to scan e.g. main, v2.1.0, release/* or latest-tag for the highest semver tag, repository_refs in configuration sets it per repository")
+	scanCmd.Flags().Bool("https", false, "Clone over https with the token from deepscan setup or the providers token variable instead of ssh keys")
+	scanCmd.Flags().String("profile", "", "Profile from 'deepscan setup --name' to scan, defaults to the first profile set up, '*' with --all scans every profile")
+	scanCmd.Flags().Bool("offline", false, "Resolve findings only from the local advisory database imported with 'deepscan db import'")
+	scanCmd.Flags().Bool("non-interactive", false, "Never prompt, missing choices are errors instead, on by default when stdin isnt a terminal e.g. in ci")
+	scanCmd.Flags().StringSlice("export", nil, "Export the findings without asking e.g. xlsx,json")
+	scanCmd.Flags().StringP("out", "o", exportformats.DefaultDirectory, "Directory exports are saved to")
 
 	rootCmd.AddCommand(scanCmd)
 }
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/RobsonDevCode/deepscan/internal/clients/models"
+	advisorytypes "github.com/RobsonDevCode/deepscan/internal/constants/advisoryTypes"
+	scannermodels "github.com/RobsonDevCode/deepscan/internal/scanner/models"
+	scannerselectionservice "github.com/RobsonDevCode/deepscan/internal/services/scannerSelectionService"
+)
+
+// fakeFileService hands back a fixed scan so the command can be run without reading a project
+type fakeFileService struct {
+	packages []models.ScannedPackage
+}
+
+func (f fakeFileService) ScanProjectFile(filepath string, options scannermodels.ScanOptions, ctx context.Context) ([]models.ScannerResponse, error) {
+	return []models.ScannerResponse{{Name: "service", ServiceName: "service", Packages: f.packages}}, nil
+}
+
+func TestRunScanFailsOnMalware(t *testing.T) {
+	vulnerable := []models.Vulnerability{{Package: models.Package{Name: "evil-pkg"}, CurrentVersion: "1.0.0"}}
+
+	tests := []struct {
+		name          string
+		packages      []models.ScannedPackage
+		expectedError string
+	}{
+		{name: "no findings"},
+		{name: "vulnerability only", packages: []models.ScannedPackage{{GhsaId: "GHSA-aaaa-bbbb-cccc", Type: advisorytypes.Reviewed, Vulnerabilities: vulnerable}}},
+		{name: "malware", packages: []models.ScannedPackage{{Aliases: []string{"MAL-2024-1234"}, Type: advisorytypes.Malware, Vulnerabilities: vulnerable}}, expectedError: "found 1 malicious packages"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			SetScanSelection(scannerselectionservice.NewScanSelection(nil, nil, fakeFileService{packages: test.packages}, nil))
+			rootCmd.SetArgs([]string{"scan", "--dir", t.TempDir(), "--non-interactive"})
+
+			err := rootCmd.ExecuteContext(context.Background())
+			if test.expectedError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), test.expectedError) {
+				t.Fatalf("expected the scan to fail with %q, got %v", test.expectedError, err)
+			}
+			if !scanCmd.SilenceUsage {
+				t.Errorf("expected usage to be silenced so the tables arent buried")
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"slices"
 
 	"github.com/fatih/color"
 
@@ -25,40 +26,65 @@ const (
 	UrlFlag      = "url"
 	AccountFlag  = "account"
 	ProviderFlag = "provider"
+	NameFlag     = "name"
 )
 
+// setupPrompt asks for a setting that wasnt given as a flag, only for the providers that need it
+type setupPrompt struct {
+	providers []string
+	text      string
+	setting   *string
+}
+
 func runSetUp(cmd *cobra.Command, urls []string) error {
 	fmt.Print("\n Setting up scanner...")
 
 	provider, _ := cmd.Flags().GetString(ProviderFlag)
 	orgUrl, _ := cmd.Flags().GetString(UrlFlag)
 	profile, _ := cmd.Flags().GetString(AccountFlag)
+	name, _ := cmd.Flags().GetString(NameFlag)
+
+	prompts := []setupPrompt{
+		{providers: []string{supportedproviders.Azure}, text: "Please Enter Azure Org Url e.g. https://dev.azure.com/your_org/: ", setting: &orgUrl},
+		{providers: []string{supportedproviders.Gitlab}, text: "Please Enter GitLab Url, leave empty for https://gitlab.com/: ", setting: &orgUrl},
+		{providers: []string{supportedproviders.BitbucketDataCenter}, text: "Please Enter Bitbucket Data Center Url e.g. https://bitbucket.your_company.com/: ", setting: &orgUrl},
+		{providers: []string{supportedproviders.Gitea, supportedproviders.Forgejo}, text: "Please Enter Gitea/Forgejo Url e.g. https://gitea.your_company.com/: ", setting: &orgUrl},
+	}
 
-	if provider == supportedproviders.Azure && orgUrl == "" {
-		scanner := bufio.NewScanner(os.Stdin)
+	scanner := bufio.NewScanner(os.Stdin)
+	for _, prompt := range prompts {
+		if !slices.Contains(prompt.providers, provider) || *prompt.setting != "" {
+			continue
+		}
 
-		fmt.Printf("\n %s", color.HiMagentaString("\nPlease Enter Azure Org Url e.g. https://dev.azure.com/your_org/: "))
+		fmt.Printf("\n %s", color.HiMagentaString("\n"+prompt.text))
 		if scanner.Scan() {
-			orgUrl = scanner.Text()
+			*prompt.setting = scanner.Text()
 		}
 	}
 
-	if err := setupservice.CreateSetupFile(orgUrl, provider, profile); err != nil {
+	if err := setupservice.CreateSetupFile(name, orgUrl, provider, profile); err != nil {
 		return err
 	}
 
-	fmt.Print(color.GreenString("\n Scanner set up, please run scan command to scan projects!"))
+	if name == "" {
+		name = setupservice.DefaultProfileName
+	}
+
+	fmt.Print(color.GreenString("\n Scanner set up as profile %s, please run scan command to scan projects!", name))
 	return nil
 }
 
 func init() {
-	setUpCmd.Flags().StringP("provider", "p", "", "Provider that the repository is saved on e.g. Github Or Azure Devops")
+	setUpCmd.Flags().StringP("provider", "p", "", "Provider that the repository is saved on e.g. github, azure, gitlab, bitbucket(cloud), bitbucket-dc(data center), gitea or forgejo")
 	setUpCmd.MarkFlagRequired("provider")
 
 	setUpCmd.Flags().StringP("url", "u", "", "Url used to connect to your org's repository.")
 
-	setUpCmd.Flags().StringP("account", "a", "", "Profile or Project(on azure) that your project repositories are listed under.")
+	setUpCmd.Flags().StringP("account", "a", "", "Profile, Project(on azure, * for every project), Group(on gitlab), Workspace(on bitbucket) or Project Key(on bitbucket-dc), User or Org(on gitea/forgejo) that your project repositories are listed under.")
 	setUpCmd.MarkFlagRequired("account")
 
+	setUpCmd.Flags().StringP("name", "n", "", "Name to save this setup under e.g. work-azure, so several providers or orgs can be scanned, defaults to default")
+
 	rootCmd.AddCommand(setUpCmd)
 }
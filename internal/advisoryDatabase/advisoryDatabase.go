@@ -0,0 +1,66 @@
+package advisorydatabase
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	osvmodels "github.com/RobsonDevCode/deepscan/internal/clients/models/osv"
+)
+
+// AdvisoryDatabase is a local copy of osv records indexed by ecosystem and package so scans can run offline
+type AdvisoryDatabase struct {
+	BuildDate       time.Time                          `json:"build_date"`
+	Vulnerabilities map[string]osvmodels.Vulnerability `json:"vulnerabilities"`
+	Index           map[string][]string                `json:"index"` // ecosystem:package - key vulnerability ids - value
+}
+
+func NewAdvisoryDatabase() *AdvisoryDatabase {
+	return &AdvisoryDatabase{
+		Vulnerabilities: make(map[string]osvmodels.Vulnerability),
+		Index:           make(map[string][]string),
+	}
+}
+
+// Add replaces any existing record with the same id so re-importing a newer dump updates the database,
+// the old record is unindexed first as an update can add or drop affected packages
+func (d *AdvisoryDatabase) Add(vulnerability osvmodels.Vulnerability) {
+	if existing, exists := d.Vulnerabilities[vulnerability.Id]; exists {
+		d.unindex(existing)
+	}
+
+	for _, affected := range vulnerability.Affected {
+		key := indexKey(affected.Package.Ecosystem, affected.Package.Name)
+		//a record can list the same package more than once, one per affected range
+		if !slices.Contains(d.Index[key], vulnerability.Id) {
+			d.Index[key] = append(d.Index[key], vulnerability.Id)
+		}
+	}
+
+	d.Vulnerabilities[vulnerability.Id] = vulnerability
+}
+
+func (d *AdvisoryDatabase) unindex(vulnerability osvmodels.Vulnerability) {
+	for _, affected := range vulnerability.Affected {
+		key := indexKey(affected.Package.Ecosystem, affected.Package.Name)
+		d.Index[key] = slices.DeleteFunc(d.Index[key], func(id string) bool {
+			return id == vulnerability.Id
+		})
+		if len(d.Index[key]) == 0 {
+			delete(d.Index, key)
+		}
+	}
+}
+
+func (d *AdvisoryDatabase) Lookup(osvEcosystem string, packageName string) []osvmodels.Vulnerability {
+	var result []osvmodels.Vulnerability
+	for _, id := range d.Index[indexKey(osvEcosystem, packageName)] {
+		result = append(result, d.Vulnerabilities[id])
+	}
+
+	return result
+}
+
+func indexKey(osvEcosystem string, packageName string) string {
+	return strings.ToLower(osvEcosystem) + ":" + strings.ToLower(packageName)
+}
@@ -0,0 +1,108 @@
+package advisorydatabase
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const (
+	databaseFolder = "deepscan/advisory-db"
+	databaseFile   = "advisories.json.gz"
+)
+
+type AdvisoryDatabaseStoreService interface {
+	Load() (*AdvisoryDatabase, error)
+	Save(database *AdvisoryDatabase) error
+	Path() string
+}
+
+type AdvisoryDatabaseStore struct {
+	path string
+}
+
+func NewAdvisoryDatabaseStore() (*AdvisoryDatabaseStore, error) {
+	userCacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("error finding user cache directory: %w", err)
+	}
+
+	return &AdvisoryDatabaseStore{
+		path: filepath.Join(userCacheDir, databaseFolder, databaseFile),
+	}, nil
+}
+
+func (s *AdvisoryDatabaseStore) Path() string {
+	return s.path
+}
+
+func (s *AdvisoryDatabaseStore) Load() (*AdvisoryDatabase, error) {
+	file, err := os.Open(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			// wrapped so import can tell a first run apart from a database it cant read
+			return nil, fmt.Errorf("no local advisory database found, please run 'deepscan db import' first, %w", err)
+		}
+		return nil, fmt.Errorf("error opening advisory database %s: %w", s.path, err)
+	}
+	defer file.Close()
+
+	return Read(file)
+}
+
+func (s *AdvisoryDatabaseStore) Save(database *AdvisoryDatabase) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("error creating advisory database directory: %w", err)
+	}
+
+	tmpPath := s.path + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("error creating advisory database %s: %w", tmpPath, err)
+	}
+
+	if err := Write(database, file); err != nil {
+		file.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("error closing advisory database %s: %w", tmpPath, err)
+	}
+
+	return os.Rename(tmpPath, s.path)
+}
+
+// Read decodes the gzipped bundle format used both on disk and for export
+func Read(reader io.Reader) (*AdvisoryDatabase, error) {
+	gzipReader, err := gzip.NewReader(reader)
+	if err != nil {
+		return nil, fmt.Errorf("error reading advisory database, is it a deepscan bundle? %w", err)
+	}
+	defer gzipReader.Close()
+
+	database := NewAdvisoryDatabase()
+	if err := json.NewDecoder(gzipReader).Decode(database); err != nil {
+		return nil, fmt.Errorf("error decoding advisory database: %w", err)
+	}
+
+	return database, nil
+}
+
+func Write(database *AdvisoryDatabase, writer io.Writer) error {
+	gzipWriter := gzip.NewWriter(writer)
+	if err := json.NewEncoder(gzipWriter).Encode(database); err != nil {
+		return fmt.Errorf("error encoding advisory database: %w", err)
+	}
+
+	if err := gzipWriter.Close(); err != nil {
+		return fmt.Errorf("error compressing advisory database: %w", err)
+	}
+
+	return nil
+}
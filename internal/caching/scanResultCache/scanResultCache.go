@@ -0,0 +1,93 @@
+package scanresultcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/RobsonDevCode/deepscan/internal/clients/models"
+)
+
+const cacheFolder = "deepscan/scan-results"
+
+type CachedScanResult struct {
+	Response                  models.ScannerResponse `json:"response"`
+	AdvisoryDatabaseTimestamp time.Time              `json:"advisory_database_timestamp"`
+	ScannedAt                 time.Time              `json:"scanned_at"`
+}
+
+type ScanResultCacheService interface {
+	Get(key string) (*CachedScanResult, error)
+	Set(key string, result CachedScanResult) error
+}
+
+type ScanResultCache struct {
+	directory string
+}
+
+func NewScanResultCache() (*ScanResultCache, error) {
+	userCacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("error finding user cache directory: %w", err)
+	}
+
+	return &ScanResultCache{
+		directory: filepath.Join(userCacheDir, cacheFolder),
+	}, nil
+}
+
+// Key content addresses a manifest so identical lockfiles share a cached result per advisory source
+func Key(manifestHash string, ecosystem string, advisorySource string) string {
+	sum := sha256.Sum256([]byte(advisorySource + ":" + ecosystem + ":" + manifestHash))
+	return hex.EncodeToString(sum[:])
+}
+
+func HashManifest(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *ScanResultCache) Get(key string) (*CachedScanResult, error) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil //not scanned before
+		}
+		return nil, fmt.Errorf("error reading cached scan result %s: %w", key, err)
+	}
+
+	var result CachedScanResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("error unmarshalling cached scan result %s: %w", key, err)
+	}
+
+	return &result, nil
+}
+
+func (c *ScanResultCache) Set(key string, result CachedScanResult) error {
+	if err := os.MkdirAll(c.directory, 0755); err != nil {
+		return fmt.Errorf("error creating scan result cache directory %s: %w", c.directory, err)
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("error marshalling scan result %s: %w", key, err)
+	}
+
+	//write then rename so a cancelled run never leaves a half written entry
+	tmpPath := c.path(key) + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("error writing scan result %s: %w", key, err)
+	}
+
+	return os.Rename(tmpPath, c.path(key))
+}
+
+func (c *ScanResultCache) path(key string) string {
+	return filepath.Join(c.directory, key+".json")
+}
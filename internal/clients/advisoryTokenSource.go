@@ -0,0 +1,41 @@
+package clients
+
+import (
+	"context"
+
+	credentialstore "github.com/RobsonDevCode/deepscan/internal/credentialStore"
+)
+
+// AdvisoryTokenSource hands out the token advisory lookups authenticate with, a github app refreshes its installation token behind it
+type AdvisoryTokenSource interface {
+	AdvisoryToken(ctx context.Context) (string, error)
+}
+
+// PersonalAccessToken the pat from config, used when no github app is set up
+type PersonalAccessToken string
+
+func (p PersonalAccessToken) AdvisoryToken(ctx context.Context) (string, error) {
+	return string(p), nil
+}
+
+// CredentialStoreTokenSource puts the credential stores environment override in front of the pat or github app,
+// so DEEPSCAN_GITHUB_TOKEN follows the same rule for advisories as it does for everything else
+type CredentialStoreTokenSource struct {
+	credentialStore credentialstore.CredentialStoreService
+	fallback        AdvisoryTokenSource
+}
+
+func NewCredentialStoreTokenSource(credentialStore credentialstore.CredentialStoreService, fallback AdvisoryTokenSource) *CredentialStoreTokenSource {
+	return &CredentialStoreTokenSource{
+		credentialStore: credentialStore,
+		fallback:        fallback,
+	}
+}
+
+func (s *CredentialStoreTokenSource) AdvisoryToken(ctx context.Context) (string, error) {
+	if _, overridden := s.credentialStore.Overridden(credentialstore.Github); overridden {
+		return s.credentialStore.Get(credentialstore.Github)
+	}
+
+	return s.fallback.AdvisoryToken(ctx)
+}
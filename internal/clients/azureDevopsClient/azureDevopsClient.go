@@ -0,0 +1,348 @@
+package azuredevopsclient
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	cache "github.com/RobsonDevCode/deepscan/internal/caching"
+	azuredevopsmodels "github.com/RobsonDevCode/deepscan/internal/clients/models/azureDevops"
+	"github.com/RobsonDevCode/deepscan/internal/configuration"
+	credentialstore "github.com/RobsonDevCode/deepscan/internal/credentialStore"
+	"github.com/sony/gobreaker"
+)
+
+const apiVersion = "7.1"
+
+// AllProjects lists repositories from every project in the organisation
+const AllProjects = "*"
+
+type AzureDevopsClientService interface {
+	GetRepositories(orgUrl string, project string, ctx context.Context) ([]azuredevopsmodels.GitRepository, error)
+	GetCommitSha(repositoryUrl string, ref string, ctx context.Context) (string, error)
+	GetItems(repositoryUrl string, sha string, ctx context.Context) ([]azuredevopsmodels.GitItem, error)
+	GetBlob(repositoryUrl string, objectId string, ctx context.Context) ([]byte, error)
+	GitCredentials() (string, string, error)
+}
+
+type AzureDevopsClient struct {
+	client              *http.Client
+	cb                  *gobreaker.CircuitBreaker
+	cache               *cache.Cache
+	credentialStore     credentialstore.CredentialStoreService
+	personalAccessToken *string
+}
+
+func NewAzureDevopsClient(config *configuration.Config, cache *cache.Cache, credentialStore credentialstore.CredentialStoreService) *AzureDevopsClient {
+	client := &http.Client{
+		Timeout: 1 * time.Minute,
+		Transport: &http.Transport{
+			MaxIdleConns:        100,
+			MaxIdleConnsPerHost: 10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+
+	cbSettings := gobreaker.Settings{
+		Name:        "azure-devops-client",
+		MaxRequests: 5,
+		Interval:    3 * time.Second,
+		Timeout:     20 * time.Second,
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= 5
+		},
+		OnStateChange: func(name string, from gobreaker.State, to gobreaker.State) {
+			fmt.Printf("Circuit breaker state changed from %v to %v\n", from, to)
+		},
+	}
+
+	return &AzureDevopsClient{
+		client:              client,
+		cb:                  gobreaker.NewCircuitBreaker(cbSettings),
+		cache:               cache,
+		credentialStore:     credentialStore,
+		personalAccessToken: &config.AzureDevopsClientSettings.PAT,
+	}
+}
+
+// GetRepositories lists the repositories in a project, or the whole organisation when the project is AllProjects
+func (c *AzureDevopsClient) GetRepositories(orgUrl string, project string, ctx context.Context) ([]azuredevopsmodels.GitRepository, error) {
+	orgUrl = withTrailingSlash(orgUrl)
+
+	response, err := c.cache.GetOrCreate(fmt.Sprintf("azure-repos-%s%s", orgUrl, project), func(entry *cache.CacheEntry) (interface{}, error) {
+		entry.Expiration = time.Now().Add(10 * time.Minute)
+
+		query := fmt.Sprintf("%s_apis/git/repositories?api-version=%s", orgUrl, apiVersion)
+		if project != AllProjects {
+			query = fmt.Sprintf("%s%s/_apis/git/repositories?api-version=%s", orgUrl, url.PathEscape(project), apiVersion)
+		}
+
+		return c.getRepositories(query, ctx)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting azure devops repositories for %s: %w", project, err)
+	}
+
+	result, ok := response.([]azuredevopsmodels.GitRepository)
+	if !ok {
+		return nil, fmt.Errorf("unexpected response type when converting response")
+	}
+
+	return result, nil
+}
+
+func (c *AzureDevopsClient) getRepositories(query string, ctx context.Context) ([]azuredevopsmodels.GitRepository, error) {
+	cbResult, err := c.cb.Execute(func() (interface{}, error) {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, query, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create http request: %w", err)
+		}
+
+		token, err := c.token()
+		if err != nil {
+			return nil, err
+		}
+		// azure devops takes a pat as the password of basic auth with an empty username
+		request.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(":"+token)))
+		request.Header.Set("Accept", "application/json")
+
+		response, err := c.client.Do(request)
+		if err != nil {
+			return nil, fmt.Errorf("client response error: %w", err)
+		}
+		defer response.Body.Close()
+
+		body, err := io.ReadAll(response.Body)
+		if err != nil {
+			return nil, fmt.Errorf("could not read body from client request %w", err)
+		}
+
+		if response.StatusCode != 200 {
+			return nil, handleAzureDevopsClientError(body, response.StatusCode)
+		}
+
+		var repositories azuredevopsmodels.GitRepositoryList
+		if err := json.Unmarshal(body, &repositories); err != nil {
+			// an expired pat gets redirected to the sign in page rather than a 401
+			return nil, fmt.Errorf("error unmarshalling azure devops repositories, check your personal access token is still valid: %w", err)
+		}
+
+		return repositories.Value, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result, ok := cbResult.([]azuredevopsmodels.GitRepository)
+	if !ok {
+		return nil, fmt.Errorf("unexpected response type when converting response")
+	}
+
+	return result, nil
+}
+
+// GetCommitSha resolves a branch, tag or sha to the commit it points at, an empty ref is the default branch.
+// azure needs to be told what kind of ref it is so a plain name is tried as a branch and then as a tag
+func (c *AzureDevopsClient) GetCommitSha(repositoryUrl string, ref string, ctx context.Context) (string, error) {
+	if ref == "" {
+		return c.getCommitSha(repositoryUrl, "", "", ctx)
+	}
+
+	if isCommitSha(ref) {
+		return c.getCommitSha(repositoryUrl, ref, "commit", ctx)
+	}
+
+	if tag, ok := strings.CutPrefix(ref, "refs/tags/"); ok {
+		return c.getCommitSha(repositoryUrl, tag, "tag", ctx)
+	}
+
+	branch := strings.TrimPrefix(ref, "refs/heads/")
+	sha, err := c.getCommitSha(repositoryUrl, branch, "branch", ctx)
+	if err == nil {
+		return sha, nil
+	}
+
+	sha, tagErr := c.getCommitSha(repositoryUrl, branch, "tag", ctx)
+	if tagErr != nil {
+		return "", err
+	}
+
+	return sha, nil
+}
+
+func (c *AzureDevopsClient) getCommitSha(repositoryUrl string, version string, versionType string, ctx context.Context) (string, error) {
+	query := fmt.Sprintf("%s/commits?searchCriteria.$top=1&api-version=%s", repositoryUrl, apiVersion)
+	if version != "" {
+		query += fmt.Sprintf("&searchCriteria.itemVersion.version=%s&searchCriteria.itemVersion.versionType=%s", url.QueryEscape(version), versionType)
+	}
+
+	body, err := c.get(query, "application/json", ctx)
+	if err != nil {
+		return "", fmt.Errorf("error resolving %s for %s: %w", version, repositoryUrl, err)
+	}
+
+	var commits azuredevopsmodels.GitCommitList
+	if err := json.Unmarshal(body, &commits); err != nil {
+		return "", fmt.Errorf("error unmarshalling azure devops commits, check your personal access token is still valid: %w", err)
+	}
+
+	if len(commits.Value) == 0 {
+		return "", fmt.Errorf("no commit found for %s in %s", version, repositoryUrl)
+	}
+
+	return commits.Value[0].CommitId, nil
+}
+
+// GetItems lists every file and folder at the commit
+func (c *AzureDevopsClient) GetItems(repositoryUrl string, sha string, ctx context.Context) ([]azuredevopsmodels.GitItem, error) {
+	response, err := c.cache.GetOrCreate("azure-items-"+repositoryUrl+"-"+sha, func(entry *cache.CacheEntry) (interface{}, error) {
+		entry.Expiration = time.Now().Add(10 * time.Minute)
+
+		body, err := c.get(fmt.Sprintf("%s/items?recursionLevel=Full&versionDescriptor.version=%s&versionDescriptor.versionType=commit&api-version=%s", repositoryUrl, sha, apiVersion), "application/json", ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		var items azuredevopsmodels.GitItemList
+		if err := json.Unmarshal(body, &items); err != nil {
+			return nil, fmt.Errorf("error unmarshalling azure devops items, check your personal access token is still valid: %w", err)
+		}
+
+		return items.Value, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting azure devops items for %s: %w", repositoryUrl, err)
+	}
+
+	result, ok := response.([]azuredevopsmodels.GitItem)
+	if !ok {
+		return nil, fmt.Errorf("unexpected response type when converting response")
+	}
+
+	return result, nil
+}
+
+func (c *AzureDevopsClient) GetBlob(repositoryUrl string, objectId string, ctx context.Context) ([]byte, error) {
+	body, err := c.get(fmt.Sprintf("%s/blobs/%s?$format=octetstream&api-version=%s", repositoryUrl, objectId, apiVersion), "application/octet-stream", ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting blob %s: %w", objectId, err)
+	}
+
+	return body, nil
+}
+
+func (c *AzureDevopsClient) get(query string, accept string, ctx context.Context) ([]byte, error) {
+	cbResult, err := c.cb.Execute(func() (interface{}, error) {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, query, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create http request: %w", err)
+		}
+
+		token, err := c.token()
+		if err != nil {
+			return nil, err
+		}
+		request.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(":"+token)))
+		request.Header.Set("Accept", accept)
+
+		response, err := c.client.Do(request)
+		if err != nil {
+			return nil, fmt.Errorf("client response error: %w", err)
+		}
+		defer response.Body.Close()
+
+		body, err := io.ReadAll(response.Body)
+		if err != nil {
+			return nil, fmt.Errorf("could not read body from client request %w", err)
+		}
+
+		if response.StatusCode != 200 {
+			return nil, handleAzureDevopsClientError(body, response.StatusCode)
+		}
+
+		return body, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result, ok := cbResult.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("unexpected response type when converting response")
+	}
+
+	return result, nil
+}
+
+// GitCredentials azure ignores the username when the password is a personal access token but git still needs one
+func (c *AzureDevopsClient) GitCredentials() (string, string, error) {
+	token, err := c.token()
+	if err != nil {
+		return "", "", err
+	}
+
+	return "pat", token, nil
+}
+
+// token goes through the credential store so the environment wins the same way it does for github,
+// ci runners then dont need the token written to configuration
+func (c *AzureDevopsClient) token() (string, error) {
+	response, err := c.cache.GetOrCreate("azure-devops-token", func(entry *cache.CacheEntry) (interface{}, error) {
+		entry.Expiration = time.Now().Add(10 * time.Minute)
+		return c.credentialStore.Get(credentialstore.AzureDevops)
+	})
+	if err != nil {
+		return "", fmt.Errorf("error reading stored azure devops token: %w", err)
+	}
+
+	token, ok := response.(string)
+	if !ok {
+		return "", fmt.Errorf("unexpected response type when converting response")
+	}
+	if token != "" {
+		return token, nil
+	}
+
+	if *c.personalAccessToken == "" || strings.HasPrefix(*c.personalAccessToken, "{") {
+		variable, _ := c.credentialStore.Overridden(credentialstore.AzureDevops)
+		return "", fmt.Errorf("no azure devops token found, set %s or azure_devops_client_settings.personal_access_token", variable)
+	}
+
+	return *c.personalAccessToken, nil
+}
+
+func handleAzureDevopsClientError(body []byte, statusCode int) error {
+	var azureError struct {
+		Message string `json:"message"`
+	}
+
+	if err := json.Unmarshal(body, &azureError); err != nil || azureError.Message == "" {
+		return fmt.Errorf("azure devops client response error status: %d", statusCode)
+	}
+
+	return fmt.Errorf("azure devops client response error status: %d, %s", statusCode, azureError.Message)
+}
+
+func isCommitSha(ref string) bool {
+	if len(ref) != 40 {
+		return false
+	}
+
+	_, err := hex.DecodeString(ref)
+	return err == nil
+}
+
+func withTrailingSlash(baseUrl string) string {
+	if strings.HasSuffix(baseUrl, "/") {
+		return baseUrl
+	}
+
+	return baseUrl + "/"
+}
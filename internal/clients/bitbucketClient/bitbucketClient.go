@@ -0,0 +1,261 @@
+package bitbucketclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	cache "github.com/RobsonDevCode/deepscan/internal/caching"
+	bitbucketmodels "github.com/RobsonDevCode/deepscan/internal/clients/models/bitbucket"
+	"github.com/RobsonDevCode/deepscan/internal/configuration"
+	"github.com/sony/gobreaker"
+)
+
+// bitbuckets max page sizes, cloud caps pagelen at 100 and data center defaults its limit to 1000
+const (
+	cloudPageLength     = 100
+	dataCenterPageLimit = 100
+)
+
+const (
+	usernameEnvironmentVariable    = "BITBUCKET_USERNAME"
+	appPasswordEnvironmentVariable = "BITBUCKET_APP_PASSWORD"
+	tokenEnvironmentVariable       = "BITBUCKET_TOKEN"
+)
+
+type BitbucketClientService interface {
+	GetWorkspaceRepositories(workspace string, ctx context.Context) ([]bitbucketmodels.CloudRepository, error)
+	GetProjectRepositories(baseUrl string, projectKey string, ctx context.Context) ([]bitbucketmodels.DataCenterRepository, error)
+	GitCredentials() (string, string, error)
+}
+
+type BitbucketClient struct {
+	client       *http.Client
+	cb           *gobreaker.CircuitBreaker
+	cloudBaseUrl *url.URL
+	cache        *cache.Cache
+	settings     *configuration.BitbucketClientSettings
+}
+
+func NewBitbucketClient(config *configuration.Config, cache *cache.Cache) (*BitbucketClient, error) {
+	client := &http.Client{
+		Timeout: 1 * time.Minute,
+		Transport: &http.Transport{
+			MaxIdleConns:        100,
+			MaxIdleConnsPerHost: 10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+
+	cbSettings := gobreaker.Settings{
+		Name:        "bitbucket-client",
+		MaxRequests: 5,
+		Interval:    3 * time.Second,
+		Timeout:     20 * time.Second,
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= 5
+		},
+		OnStateChange: func(name string, from gobreaker.State, to gobreaker.State) {
+			fmt.Printf("Circuit breaker state changed from %v to %v\n", from, to)
+		},
+	}
+
+	cloudBaseUrl, err := url.Parse(config.BitbucketClientSettings.CloudBaseUrl)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing base url to a url type, %w", err)
+	}
+
+	return &BitbucketClient{
+		client:       client,
+		cb:           gobreaker.NewCircuitBreaker(cbSettings),
+		cloudBaseUrl: cloudBaseUrl,
+		cache:        cache,
+		settings:     &config.BitbucketClientSettings,
+	}, nil
+}
+
+// GetWorkspaceRepositories cloud pages hand us the full url of the next page until there isnt one
+func (c *BitbucketClient) GetWorkspaceRepositories(workspace string, ctx context.Context) ([]bitbucketmodels.CloudRepository, error) {
+	response, err := c.cache.GetOrCreate("bitbucket-cloud-repos-"+workspace, func(entry *cache.CacheEntry) (interface{}, error) {
+		entry.Expiration = time.Now().Add(10 * time.Minute)
+
+		var result []bitbucketmodels.CloudRepository
+		next := fmt.Sprintf("%s2.0/repositories/%s?pagelen=%d", c.cloudBaseUrl, url.PathEscape(workspace), cloudPageLength)
+		for next != "" {
+			var page bitbucketmodels.CloudRepositoryPage
+			if err := c.get(next, &page, ctx); err != nil {
+				return nil, err
+			}
+
+			result = append(result, page.Values...)
+			next = page.Next
+		}
+
+		return result, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting bitbucket repositories for workspace %s: %w", workspace, err)
+	}
+
+	result, ok := response.([]bitbucketmodels.CloudRepository)
+	if !ok {
+		return nil, fmt.Errorf("unexpected response type when converting response")
+	}
+
+	return result, nil
+}
+
+// GetProjectRepositories data center pages by offset, nextPageStart is only set while isLastPage is false
+func (c *BitbucketClient) GetProjectRepositories(baseUrl string, projectKey string, ctx context.Context) ([]bitbucketmodels.DataCenterRepository, error) {
+	response, err := c.cache.GetOrCreate("bitbucket-dc-repos-"+baseUrl+projectKey, func(entry *cache.CacheEntry) (interface{}, error) {
+		entry.Expiration = time.Now().Add(10 * time.Minute)
+
+		var result []bitbucketmodels.DataCenterRepository
+		start := 0
+		for {
+			query := fmt.Sprintf("%srest/api/1.0/projects/%s/repos?limit=%d&start=%d",
+				withTrailingSlash(baseUrl), url.PathEscape(projectKey), dataCenterPageLimit, start)
+
+			var page bitbucketmodels.DataCenterRepositoryPage
+			if err := c.get(query, &page, ctx); err != nil {
+				return nil, err
+			}
+
+			result = append(result, page.Values...)
+			if page.IsLastPage {
+				break
+			}
+			start = page.NextPageStart
+		}
+
+		return result, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting bitbucket repositories for project %s: %w", projectKey, err)
+	}
+
+	result, ok := response.([]bitbucketmodels.DataCenterRepository)
+	if !ok {
+		return nil, fmt.Errorf("unexpected response type when converting response")
+	}
+
+	return result, nil
+}
+
+func (c *BitbucketClient) get(query string, target interface{}, ctx context.Context) error {
+	_, err := c.cb.Execute(func() (interface{}, error) {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, query, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create http request: %w", err)
+		}
+
+		if err := c.authorize(request); err != nil {
+			return nil, err
+		}
+		request.Header.Set("Accept", "application/json")
+
+		response, err := c.client.Do(request)
+		if err != nil {
+			return nil, fmt.Errorf("client response error: %w", err)
+		}
+		defer response.Body.Close()
+
+		body, err := io.ReadAll(response.Body)
+		if err != nil {
+			return nil, fmt.Errorf("could not read body from client request %w", err)
+		}
+
+		if response.StatusCode != 200 {
+			return nil, handleBitbucketClientError(body, response.StatusCode)
+		}
+
+		if err := json.Unmarshal(body, target); err != nil {
+			return nil, fmt.Errorf("error unmarshalling bitbucket repositories: %w", err)
+		}
+
+		return nil, nil
+	})
+
+	return err
+}
+
+// GitCredentials access tokens clone as x-token-auth, app passwords clone as the account they belong to
+func (c *BitbucketClient) GitCredentials() (string, string, error) {
+	if token := firstSet(os.Getenv(tokenEnvironmentVariable), c.settings.AccessToken); token != "" {
+		return "x-token-auth", token, nil
+	}
+
+	username := firstSet(os.Getenv(usernameEnvironmentVariable), c.settings.Username)
+	appPassword := firstSet(os.Getenv(appPasswordEnvironmentVariable), c.settings.AppPassword)
+	if username != "" && appPassword != "" {
+		return username, appPassword, nil
+	}
+
+	return "", "", fmt.Errorf("no bitbucket credentials found, set %s or %s and %s", tokenEnvironmentVariable, usernameEnvironmentVariable, appPasswordEnvironmentVariable)
+}
+
+// authorize http access tokens are sent as bearer tokens, app passwords use basic auth with the account username
+func (c *BitbucketClient) authorize(request *http.Request) error {
+	token := firstSet(os.Getenv(tokenEnvironmentVariable), c.settings.AccessToken)
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	}
+
+	username := firstSet(os.Getenv(usernameEnvironmentVariable), c.settings.Username)
+	appPassword := firstSet(os.Getenv(appPasswordEnvironmentVariable), c.settings.AppPassword)
+	if username != "" && appPassword != "" {
+		request.SetBasicAuth(username, appPassword)
+		return nil
+	}
+
+	return fmt.Errorf("no bitbucket credentials found, set %s or %s and %s", tokenEnvironmentVariable, usernameEnvironmentVariable, appPasswordEnvironmentVariable)
+}
+
+func handleBitbucketClientError(body []byte, statusCode int) error {
+	// cloud returns {"error": {"message"}} and data center returns {"errors": [{"message"}]}
+	var bitbucketError struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+
+	if err := json.Unmarshal(body, &bitbucketError); err == nil {
+		if bitbucketError.Error.Message != "" {
+			return fmt.Errorf("bitbucket client response error status: %d, %s", statusCode, bitbucketError.Error.Message)
+		}
+		if len(bitbucketError.Errors) > 0 {
+			return fmt.Errorf("bitbucket client response error status: %d, %s", statusCode, bitbucketError.Errors[0].Message)
+		}
+	}
+
+	return fmt.Errorf("bitbucket client response error status: %d", statusCode)
+}
+
+// firstSet skips the {FILL_IN_CONFIG} placeholders configuration ships with
+func firstSet(values ...string) string {
+	for _, value := range values {
+		if value != "" && !strings.HasPrefix(value, "{") {
+			return value
+		}
+	}
+
+	return ""
+}
+
+func withTrailingSlash(baseUrl string) string {
+	if strings.HasSuffix(baseUrl, "/") {
+		return baseUrl
+	}
+
+	return baseUrl + "/"
+}
@@ -0,0 +1,163 @@
+package bitbucketclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	cache "github.com/RobsonDevCode/deepscan/internal/caching"
+	"github.com/RobsonDevCode/deepscan/internal/configuration"
+)
+
+func newBitbucketClient(t *testing.T, cloudBaseUrl string, settings configuration.BitbucketClientSettings) *BitbucketClient {
+	t.Setenv(tokenEnvironmentVariable, "")
+	t.Setenv(usernameEnvironmentVariable, "")
+	t.Setenv(appPasswordEnvironmentVariable, "")
+
+	config := &configuration.Config{BitbucketClientSettings: settings}
+	config.BitbucketClientSettings.CloudBaseUrl = cloudBaseUrl + "/"
+	client, err := NewBitbucketClient(config, &cache.Cache{})
+	if err != nil {
+		t.Fatalf("unexpected error creating client: %v", err)
+	}
+
+	return client
+}
+
+func TestGetWorkspaceRepositoriesFollowsNext(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer test-token" {
+			t.Errorf("expected the access token as a bearer token, got %q", got)
+		}
+		if r.URL.Path != "/2.0/repositories/acme" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+
+		switch r.URL.Query().Get("page") {
+		case "":
+			if got := r.URL.Query().Get("pagelen"); got != fmt.Sprint(cloudPageLength) {
+				t.Errorf("expected pagelen %d, got %s", cloudPageLength, got)
+			}
+			fmt.Fprintf(w, `{"values":[{"full_name":"acme/api"},{"full_name":"acme/web"}],"next":"%s/2.0/repositories/acme?pagelen=100&page=2"}`, server.URL)
+		case "2":
+			fmt.Fprintf(w, `{"values":[{"full_name":"acme/worker"}],"next":"%s/2.0/repositories/acme?pagelen=100&page=3"}`, server.URL)
+		case "3":
+			fmt.Fprint(w, `{"values":[{"full_name":"acme/docs"}]}`)
+		default:
+			t.Errorf("unexpected page %s", r.URL.Query().Get("page"))
+		}
+	}))
+	defer server.Close()
+
+	client := newBitbucketClient(t, server.URL, configuration.BitbucketClientSettings{AccessToken: "test-token"})
+	repositories, err := client.GetWorkspaceRepositories("acme", context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var names []string
+	for _, repository := range repositories {
+		names = append(names, repository.FullName)
+	}
+	if got := strings.Join(names, ","); got != "acme/api,acme/web,acme/worker,acme/docs" {
+		t.Errorf("expected every page in order, got %s", got)
+	}
+}
+
+func TestGetProjectRepositoriesPagesByStart(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "someone" || password != "app-password" {
+			t.Errorf("expected basic auth with the app password, got %q %q", username, password)
+		}
+		if r.URL.Path != "/bitbucket/rest/api/1.0/projects/PAY/repos" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+
+		switch r.URL.Query().Get("start") {
+		case "0":
+			fmt.Fprint(w, `{"values":[{"slug":"ledger"},{"slug":"billing"}],"isLastPage":false,"nextPageStart":2}`)
+		case "2":
+			fmt.Fprint(w, `{"values":[{"slug":"invoices"}],"isLastPage":false,"nextPageStart":3}`)
+		case "3":
+			//isLastPage ends the paging, some servers still send a nextPageStart with it
+			fmt.Fprint(w, `{"values":[{"slug":"refunds"}],"isLastPage":true,"nextPageStart":4}`)
+		default:
+			t.Errorf("unexpected start %s", r.URL.Query().Get("start"))
+		}
+	}))
+	defer server.Close()
+
+	client := newBitbucketClient(t, server.URL, configuration.BitbucketClientSettings{Username: "someone", AppPassword: "app-password"})
+	repositories, err := client.GetProjectRepositories(server.URL+"/bitbucket", "PAY", context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var slugs []string
+	for _, repository := range repositories {
+		slugs = append(slugs, repository.Slug)
+	}
+	if got := strings.Join(slugs, ","); got != "ledger,billing,invoices,refunds" {
+		t.Errorf("expected every page in order, got %s", got)
+	}
+}
+
+func TestHandleBitbucketClientError(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{name: "cloud error object", body: `{"type":"error","error":{"message":"No workspace with identifier 'acme'."}}`, expected: "bitbucket client response error status: 404, No workspace with identifier 'acme'."},
+		{name: "data center errors list", body: `{"errors":[{"message":"Project PAY does not exist."}]}`, expected: "bitbucket client response error status: 404, Project PAY does not exist."},
+		{name: "proxy error page", body: `<html>not found</html>`, expected: "bitbucket client response error status: 404"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := handleBitbucketClientError([]byte(test.body), http.StatusNotFound).Error(); got != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestGitCredentials(t *testing.T) {
+	tests := []struct {
+		name             string
+		settings         configuration.BitbucketClientSettings
+		expectedUsername string
+		expectedPassword string
+		expectError      bool
+	}{
+		{name: "access token", settings: configuration.BitbucketClientSettings{AccessToken: "test-token", Username: "someone", AppPassword: "app-password"}, expectedUsername: "x-token-auth", expectedPassword: "test-token"},
+		{name: "app password", settings: configuration.BitbucketClientSettings{Username: "someone", AppPassword: "app-password"}, expectedUsername: "someone", expectedPassword: "app-password"},
+		{name: "placeholders", settings: configuration.BitbucketClientSettings{AccessToken: "{FILL_IN_CONFIG}", Username: "{FILL_IN_CONFIG}", AppPassword: "{FILL_IN_CONFIG}"}, expectError: true},
+		{name: "username without app password", settings: configuration.BitbucketClientSettings{Username: "someone"}, expectError: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := newBitbucketClient(t, "https://api.bitbucket.org", test.settings)
+			username, password, err := client.GitCredentials()
+			if test.expectError {
+				if err == nil {
+					t.Errorf("expected an error, got %s %s", username, password)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if username != test.expectedUsername || password != test.expectedPassword {
+				t.Errorf("expected %s %s, got %s %s", test.expectedUsername, test.expectedPassword, username, password)
+			}
+		})
+	}
+}
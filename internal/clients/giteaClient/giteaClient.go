@@ -0,0 +1,218 @@
+package giteaclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	cache "github.com/RobsonDevCode/deepscan/internal/caching"
+	giteamodels "github.com/RobsonDevCode/deepscan/internal/clients/models/gitea"
+	"github.com/RobsonDevCode/deepscan/internal/configuration"
+	"github.com/RobsonDevCode/deepscan/internal/extensions"
+	"github.com/sony/gobreaker"
+)
+
+// gitea caps limit at the servers MAX_RESPONSE_ITEMS which defaults to 50, a server set lower returns short pages
+// so the link header decides when to stop rather than the page size
+const repositoriesPerPage = 50
+
+const tokenEnvironmentVariable = "GITEA_TOKEN"
+
+var errOwnerNotFound = errors.New("owner not found")
+
+type GiteaClientService interface {
+	GetOwnerRepositories(baseUrl string, owner string, ctx context.Context) ([]giteamodels.GiteaRepository, error)
+	GitCredentials() (string, string, error)
+}
+
+type repositoryPage struct {
+	repositories []giteamodels.GiteaRepository
+	next         string
+}
+
+type GiteaClient struct {
+	client              *http.Client
+	cb                  *gobreaker.CircuitBreaker
+	cache               *cache.Cache
+	personalAccessToken *string
+}
+
+func NewGiteaClient(config *configuration.Config, cache *cache.Cache) *GiteaClient {
+	client := &http.Client{
+		Timeout: 1 * time.Minute,
+		Transport: &http.Transport{
+			MaxIdleConns:        100,
+			MaxIdleConnsPerHost: 10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+
+	cbSettings := gobreaker.Settings{
+		Name:        "gitea-client",
+		MaxRequests: 5,
+		Interval:    3 * time.Second,
+		Timeout:     20 * time.Second,
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= 5
+		},
+		IsSuccessful: func(err error) bool {
+			//a missing org just means we should look for a user, it isnt the server failing
+			return err == nil || errors.Is(err, errOwnerNotFound)
+		},
+		OnStateChange: func(name string, from gobreaker.State, to gobreaker.State) {
+			fmt.Printf("Circuit breaker state changed from %v to %v\n", from, to)
+		},
+	}
+
+	return &GiteaClient{
+		client:              client,
+		cb:                  gobreaker.NewCircuitBreaker(cbSettings),
+		cache:               cache,
+		personalAccessToken: &config.GiteaClientSettings.PAT,
+	}
+}
+
+// GetOwnerRepositories gitea splits org and user repositories across two endpoints, so we try the org first and fall back to the user
+func (c *GiteaClient) GetOwnerRepositories(baseUrl string, owner string, ctx context.Context) ([]giteamodels.GiteaRepository, error) {
+	response, err := c.cache.GetOrCreate("gitea-repos-"+baseUrl+owner, func(entry *cache.CacheEntry) (interface{}, error) {
+		entry.Expiration = time.Now().Add(10 * time.Minute)
+
+		repositories, err := c.getAllPages(fmt.Sprintf("%sapi/v1/orgs/%s/repos", withTrailingSlash(baseUrl), url.PathEscape(owner)), ctx)
+		if errors.Is(err, errOwnerNotFound) {
+			repositories, err = c.getAllPages(fmt.Sprintf("%sapi/v1/users/%s/repos", withTrailingSlash(baseUrl), url.PathEscape(owner)), ctx)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return repositories, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting gitea repositories for %s: %w", owner, err)
+	}
+
+	result, ok := response.([]giteamodels.GiteaRepository)
+	if !ok {
+		return nil, fmt.Errorf("unexpected response type when converting response")
+	}
+
+	return result, nil
+}
+
+// getAllPages follows the link header until there is no next page
+func (c *GiteaClient) getAllPages(query string, ctx context.Context) ([]giteamodels.GiteaRepository, error) {
+	var result []giteamodels.GiteaRepository
+	next := fmt.Sprintf("%s?limit=%d&page=1", query, repositoriesPerPage)
+	for next != "" {
+		page, err := c.getPage(next, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, page.repositories...)
+		next = page.next
+	}
+
+	return result, nil
+}
+
+func (c *GiteaClient) getPage(query string, ctx context.Context) (repositoryPage, error) {
+	cbResult, err := c.cb.Execute(func() (interface{}, error) {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, query, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create http request: %w", err)
+		}
+
+		token, err := c.token()
+		if err != nil {
+			return nil, err
+		}
+		request.Header.Set("Authorization", "token "+token)
+		request.Header.Set("Accept", "application/json")
+
+		response, err := c.client.Do(request)
+		if err != nil {
+			return nil, fmt.Errorf("client response error: %w", err)
+		}
+		defer response.Body.Close()
+
+		body, err := io.ReadAll(response.Body)
+		if err != nil {
+			return nil, fmt.Errorf("could not read body from client request %w", err)
+		}
+
+		if response.StatusCode == http.StatusNotFound {
+			return nil, errOwnerNotFound
+		}
+		if response.StatusCode != 200 {
+			return nil, handleGiteaClientError(body, response.StatusCode)
+		}
+
+		var repositories []giteamodels.GiteaRepository
+		if err := json.Unmarshal(body, &repositories); err != nil {
+			return nil, fmt.Errorf("error unmarshalling gitea repositories: %w", err)
+		}
+
+		return repositoryPage{repositories: repositories, next: extensions.NextPageLink(response.Header.Get("Link"))}, nil
+	})
+	if err != nil {
+		return repositoryPage{}, err
+	}
+
+	result, ok := cbResult.(repositoryPage)
+	if !ok {
+		return repositoryPage{}, fmt.Errorf("unexpected response type when converting response")
+	}
+
+	return result, nil
+}
+
+// GitCredentials gitea checks the password for an access token before the username so any username works
+func (c *GiteaClient) GitCredentials() (string, string, error) {
+	token, err := c.token()
+	if err != nil {
+		return "", "", err
+	}
+
+	return "oauth2", token, nil
+}
+
+// token prefers the environment so ci runners dont need the token written to configuration
+func (c *GiteaClient) token() (string, error) {
+	if token := os.Getenv(tokenEnvironmentVariable); token != "" {
+		return token, nil
+	}
+
+	if *c.personalAccessToken == "" || strings.HasPrefix(*c.personalAccessToken, "{") {
+		return "", fmt.Errorf("no gitea token found, set %s or gitea_client_settings.personal_access_token", tokenEnvironmentVariable)
+	}
+
+	return *c.personalAccessToken, nil
+}
+
+func handleGiteaClientError(body []byte, statusCode int) error {
+	var giteaError struct {
+		Message string `json:"message"`
+	}
+
+	if err := json.Unmarshal(body, &giteaError); err != nil || giteaError.Message == "" {
+		return fmt.Errorf("gitea client response error status: %d", statusCode)
+	}
+
+	return fmt.Errorf("gitea client response error status: %d, %s", statusCode, giteaError.Message)
+}
+
+func withTrailingSlash(baseUrl string) string {
+	if strings.HasSuffix(baseUrl, "/") {
+		return baseUrl
+	}
+
+	return baseUrl + "/"
+}
@@ -0,0 +1,122 @@
+package giteaclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	cache "github.com/RobsonDevCode/deepscan/internal/caching"
+	"github.com/RobsonDevCode/deepscan/internal/configuration"
+)
+
+func newGiteaClient(t *testing.T) *GiteaClient {
+	t.Setenv(tokenEnvironmentVariable, "")
+
+	config := &configuration.Config{}
+	config.GiteaClientSettings.PAT = "test-token"
+	return NewGiteaClient(config, &cache.Cache{})
+}
+
+func TestGetOwnerRepositoriesFollowsLinkHeader(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "token test-token" {
+			t.Errorf("expected the token in the authorization header, got %q", got)
+		}
+
+		page := r.URL.Query().Get("page")
+		switch page {
+		case "1":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/api/v1/orgs/acme/repos?limit=50&page=2>; rel="next", <%s/api/v1/orgs/acme/repos?limit=50&page=3>; rel="last"`, server.URL, server.URL))
+		case "2":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/api/v1/orgs/acme/repos?limit=50&page=3>; rel="next", <%s/api/v1/orgs/acme/repos?limit=50&page=1>; rel="first"`, server.URL, server.URL))
+		case "3":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/api/v1/orgs/acme/repos?limit=50&page=1>; rel="first"`, server.URL))
+		default:
+			t.Errorf("unexpected page %q", page)
+		}
+
+		//short pages, as a server with a low MAX_RESPONSE_ITEMS returns, must not end the paging early
+		fmt.Fprintf(w, `[{"full_name":"acme/repo-%s"}]`, page)
+	}))
+	defer server.Close()
+
+	repositories, err := newGiteaClient(t).GetOwnerRepositories(server.URL, "acme", context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var names []string
+	for _, repository := range repositories {
+		names = append(names, repository.Name)
+	}
+	if got := strings.Join(names, ","); got != "acme/repo-1,acme/repo-2,acme/repo-3" {
+		t.Errorf("expected every page in order, got %s", got)
+	}
+}
+
+func TestGetOwnerRepositoriesFallsBackToUser(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/api/v1/orgs/"):
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"GetOrgByName"}`)
+		case r.URL.Path == "/api/v1/users/someone/repos":
+			fmt.Fprint(w, `[{"full_name":"someone/dotfiles"}]`)
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	repositories, err := newGiteaClient(t).GetOwnerRepositories(server.URL+"/", "someone", context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(repositories) != 1 || repositories[0].Name != "someone/dotfiles" {
+		t.Errorf("expected the users repositories, got %+v", repositories)
+	}
+}
+
+func TestGetOwnerRepositoriesOwnerMissing(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message":"not found"}`)
+	}))
+	defer server.Close()
+
+	_, err := newGiteaClient(t).GetOwnerRepositories(server.URL, "acme", context.Background())
+	if !errors.Is(err, errOwnerNotFound) {
+		t.Errorf("expected owner not found, got %v", err)
+	}
+
+	if got := strings.Join(paths, ","); got != "/api/v1/orgs/acme/repos,/api/v1/users/acme/repos" {
+		t.Errorf("expected the org then the user to be checked, got %s", got)
+	}
+}
+
+func TestGetOwnerRepositoriesOnlyFallsBackWhenOwnerMissing(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"message":"token is required"}`)
+	}))
+	defer server.Close()
+
+	// a bad token would fail the user lookup too, retrying it would only hide the real error
+	if _, err := newGiteaClient(t).GetOwnerRepositories(server.URL, "acme", context.Background()); err == nil {
+		t.Fatalf("expected an error for a rejected token")
+	}
+
+	if got := strings.Join(paths, ","); got != "/api/v1/orgs/acme/repos" {
+		t.Errorf("expected only the org to be checked, got %s", got)
+	}
+}
@@ -0,0 +1,237 @@
+package githubappclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	cache "github.com/RobsonDevCode/deepscan/internal/caching"
+	"github.com/RobsonDevCode/deepscan/internal/clients/models"
+	authenticaionmodels "github.com/RobsonDevCode/deepscan/internal/clients/models/githubAuthentication"
+	"github.com/RobsonDevCode/deepscan/internal/configuration"
+	"github.com/sony/gobreaker"
+)
+
+var errInstallationNotFound = errors.New("installation not found")
+
+type GithubAppClientService interface {
+	GetInstallationId(owner string, appJwt string, ctx context.Context) (int64, error)
+	GetInstallations(appJwt string, ctx context.Context) ([]authenticaionmodels.Installation, error)
+	CreateInstallationToken(installationId int64, appJwt string, ctx context.Context) (authenticaionmodels.InstallationToken, error)
+}
+
+// GithubAppClient talks to the app endpoints, these authenticate with the apps jwt rather than a user or installation token
+type GithubAppClient struct {
+	client  *http.Client
+	cb      *gobreaker.CircuitBreaker
+	baseUrl *url.URL
+	cache   *cache.Cache
+}
+
+func NewGithubAppClient(config *configuration.Config, cache *cache.Cache) (*GithubAppClient, error) {
+	client := &http.Client{
+		Timeout: 1 * time.Minute,
+		Transport: &http.Transport{
+			MaxIdleConns:        100,
+			MaxIdleConnsPerHost: 10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+
+	cbSettings := gobreaker.Settings{
+		Name:        "github-app-client",
+		MaxRequests: 5,
+		Interval:    3 * time.Second,
+		Timeout:     20 * time.Second,
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= 5
+		},
+		IsSuccessful: func(err error) bool {
+			//an org without the app installed means we should look for a user, it isnt the server failing
+			return err == nil || errors.Is(err, errInstallationNotFound)
+		},
+		OnStateChange: func(name string, from gobreaker.State, to gobreaker.State) {
+			fmt.Printf("Circuit breaker state changed from %v to %v\n", from, to)
+		},
+	}
+
+	//defaults to the same api as the github client, set base_url to point at a stub
+	rawUrl := config.GithubAppSettings.BaseUrl
+	if rawUrl == "" {
+		rawUrl = config.GithubClientSettings.BaseUrl
+	}
+
+	baseUrl, err := url.Parse(rawUrl)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing base url to a url type, %w", err)
+	}
+
+	return &GithubAppClient{
+		client:  client,
+		cb:      gobreaker.NewCircuitBreaker(cbSettings),
+		baseUrl: baseUrl,
+		cache:   cache,
+	}, nil
+}
+
+// GetInstallationId github splits org and user installations across two endpoints, so we try the org first and fall back to the user
+func (c *GithubAppClient) GetInstallationId(owner string, appJwt string, ctx context.Context) (int64, error) {
+	response, err := c.cache.GetOrCreate("github-app-installation-"+owner, func(entry *cache.CacheEntry) (interface{}, error) {
+		entry.Expiration = time.Now().Add(10 * time.Minute)
+
+		installation, err := c.getInstallation(fmt.Sprintf("%sorgs/%s/installation", c.baseUrl, url.PathEscape(owner)), appJwt, ctx)
+		if errors.Is(err, errInstallationNotFound) {
+			installation, err = c.getInstallation(fmt.Sprintf("%susers/%s/installation", c.baseUrl, url.PathEscape(owner)), appJwt, ctx)
+		}
+		if errors.Is(err, errInstallationNotFound) {
+			return nil, fmt.Errorf("the github app isnt installed on %s", owner)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return installation.Id, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	result, ok := response.(int64)
+	if !ok {
+		return 0, fmt.Errorf("unexpected response type when converting response")
+	}
+
+	return result, nil
+}
+
+func (c *GithubAppClient) getInstallation(url string, appJwt string, ctx context.Context) (authenticaionmodels.Installation, error) {
+	cbResult, err := c.cb.Execute(func() (interface{}, error) {
+		body, statusCode, err := c.send(http.MethodGet, url, appJwt, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if statusCode == http.StatusNotFound {
+			return nil, errInstallationNotFound
+		}
+		if statusCode != http.StatusOK {
+			return nil, handleGithubAppClientError(body, statusCode)
+		}
+
+		var result authenticaionmodels.Installation
+		if err := json.Unmarshal(body, &result); err != nil {
+			return nil, handleGithubAppClientError(body, statusCode)
+		}
+
+		return result, nil
+	})
+	if err != nil {
+		return authenticaionmodels.Installation{}, err
+	}
+
+	result, ok := cbResult.(authenticaionmodels.Installation)
+	if !ok {
+		return authenticaionmodels.Installation{}, fmt.Errorf("unexpected response type when converting response")
+	}
+
+	return result, nil
+}
+
+// GetInstallations only the first page, an app used for scanning is rarely installed on more than a handful of owners
+func (c *GithubAppClient) GetInstallations(appJwt string, ctx context.Context) ([]authenticaionmodels.Installation, error) {
+	cbResult, err := c.cb.Execute(func() (interface{}, error) {
+		body, statusCode, err := c.send(http.MethodGet, fmt.Sprintf("%sapp/installations?per_page=100", c.baseUrl), appJwt, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if statusCode != http.StatusOK {
+			return nil, handleGithubAppClientError(body, statusCode)
+		}
+
+		var result []authenticaionmodels.Installation
+		if err := json.Unmarshal(body, &result); err != nil {
+			return nil, handleGithubAppClientError(body, statusCode)
+		}
+
+		return result, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result, ok := cbResult.([]authenticaionmodels.Installation)
+	if !ok {
+		return nil, fmt.Errorf("unexpected response type when converting response")
+	}
+
+	return result, nil
+}
+
+// CreateInstallationToken isnt cached here, the caller caches it until just before it expires
+func (c *GithubAppClient) CreateInstallationToken(installationId int64, appJwt string, ctx context.Context) (authenticaionmodels.InstallationToken, error) {
+	cbResult, err := c.cb.Execute(func() (interface{}, error) {
+		body, statusCode, err := c.send(http.MethodPost, fmt.Sprintf("%sapp/installations/%d/access_tokens", c.baseUrl, installationId), appJwt, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if statusCode != http.StatusCreated {
+			return nil, handleGithubAppClientError(body, statusCode)
+		}
+
+		var result authenticaionmodels.InstallationToken
+		if err := json.Unmarshal(body, &result); err != nil {
+			return nil, handleGithubAppClientError(body, statusCode)
+		}
+
+		return result, nil
+	})
+	if err != nil {
+		return authenticaionmodels.InstallationToken{}, err
+	}
+
+	result, ok := cbResult.(authenticaionmodels.InstallationToken)
+	if !ok {
+		return authenticaionmodels.InstallationToken{}, fmt.Errorf("unexpected response type when converting response")
+	}
+
+	return result, nil
+}
+
+func (c *GithubAppClient) send(method string, url string, appJwt string, ctx context.Context) ([]byte, int, error) {
+	request, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create http request: %w", err)
+	}
+
+	request.Header.Set("Authorization", "Bearer "+appJwt)
+	request.Header.Set("Accept", "application/vnd.github+json")
+
+	response, err := c.client.Do(request)
+	if err != nil {
+		return nil, 0, fmt.Errorf("client response error: %w", err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("could not read body from client request %w", err)
+	}
+
+	return body, response.StatusCode, nil
+}
+
+func handleGithubAppClientError(body []byte, statusCode int) error {
+	var clientError models.Error
+	if err := json.Unmarshal(body, &clientError); err != nil {
+		return fmt.Errorf("failed to read client error status code %d: %w", statusCode, err)
+	}
+
+	return fmt.Errorf("client response error status: %d, %v", statusCode, clientError)
+}
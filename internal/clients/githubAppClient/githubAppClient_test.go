@@ -0,0 +1,139 @@
+package githubappclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cache "github.com/RobsonDevCode/deepscan/internal/caching"
+	"github.com/RobsonDevCode/deepscan/internal/configuration"
+)
+
+const testJwt = "header.claims.signature"
+
+func newAppClient(t *testing.T, baseUrl string) *GithubAppClient {
+	config := &configuration.Config{}
+	config.GithubAppSettings.BaseUrl = baseUrl + "/"
+	client, err := NewGithubAppClient(config, &cache.Cache{})
+	if err != nil {
+		t.Fatalf("unexpected error creating client: %v", err)
+	}
+
+	return client
+}
+
+func checkHeaders(t *testing.T, r *http.Request) {
+	if got := r.Header.Get("Authorization"); got != "Bearer "+testJwt {
+		t.Errorf("expected the app jwt as a bearer token, got %q", got)
+	}
+	if got := r.Header.Get("Accept"); got != "application/vnd.github+json" {
+		t.Errorf("expected the github json accept header, got %q", got)
+	}
+}
+
+func TestGetInstallationIdFallsBackToUser(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		checkHeaders(t, r)
+
+		switch r.URL.Path {
+		case "/orgs/someone/installation":
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"Not Found"}`)
+		case "/users/someone/installation":
+			fmt.Fprint(w, `{"id":42,"account":{"login":"someone"}}`)
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := newAppClient(t, server.URL)
+	for range 2 {
+		installationId, err := client.GetInstallationId("someone", testJwt, context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if installationId != 42 {
+			t.Errorf("expected installation 42, got %d", installationId)
+		}
+	}
+
+	if got := requests.Load(); got != 2 {
+		t.Errorf("expected the org and user lookups once then the cache, got %d requests", got)
+	}
+}
+
+func TestGetInstallationIdNotInstalled(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message":"Not Found"}`)
+	}))
+	defer server.Close()
+
+	_, err := newAppClient(t, server.URL).GetInstallationId("acme", testJwt, context.Background())
+	if err == nil || err.Error() != "the github app isnt installed on acme" {
+		t.Errorf("expected the app not installed error, got %v", err)
+	}
+
+	if got := strings.Join(paths, ","); got != "/orgs/acme/installation,/users/acme/installation" {
+		t.Errorf("expected the org then the user to be checked, got %s", got)
+	}
+}
+
+func TestGetInstallations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		checkHeaders(t, r)
+		if r.URL.Path != "/app/installations" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+
+		fmt.Fprint(w, `[{"id":7,"account":{"login":"acme"}},{"id":9,"account":{"login":"someone"}}]`)
+	}))
+	defer server.Close()
+
+	installations, err := newAppClient(t, server.URL).GetInstallations(testJwt, context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(installations) != 2 || installations[0].Id != 7 || installations[1].Account.Login != "someone" {
+		t.Errorf("expected both installations, got %+v", installations)
+	}
+}
+
+func TestCreateInstallationTokenIsNeverCached(t *testing.T) {
+	expiresAt := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	var mintings atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		checkHeaders(t, r)
+		if r.Method != http.MethodPost || r.URL.Path != "/app/installations/42/access_tokens" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		minting := mintings.Add(1)
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"token":"ghs_%d","expires_at":"%s"}`, minting, expiresAt.Format(time.RFC3339))
+	}))
+	defer server.Close()
+
+	// the authenticator decides when a token is due a refresh so the client must always ask github for a new one
+	client := newAppClient(t, server.URL)
+	for _, expected := range []string{"ghs_1", "ghs_2"} {
+		token, err := client.CreateInstallationToken(42, testJwt, context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if token.Token != expected || !token.ExpiresAt.Equal(expiresAt) {
+			t.Errorf("expected %s expiring at %s, got %+v", expected, expiresAt, token)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package clients
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -12,26 +13,49 @@ import (
 
 	cache "github.com/RobsonDevCode/deepscan/internal/caching"
 	"github.com/RobsonDevCode/deepscan/internal/clients/models"
+	dependencygraphmodels "github.com/RobsonDevCode/deepscan/internal/clients/models/dependencyGraph"
+	authenticaionmodels "github.com/RobsonDevCode/deepscan/internal/clients/models/githubAuthentication"
 	githubreposmodels "github.com/RobsonDevCode/deepscan/internal/clients/models/repos"
 	"github.com/RobsonDevCode/deepscan/internal/configuration"
+	advisorytypes "github.com/RobsonDevCode/deepscan/internal/constants/advisoryTypes"
+	"github.com/RobsonDevCode/deepscan/internal/extensions"
 	"github.com/sony/gobreaker"
 )
 
 type GithubClientService interface {
 	GetPackagesInfo(ecosystem string, packageAndVersions map[string]string, ctx context.Context) ([]models.ScannedPackage, error)
-	GetRepositories(accessToken string, ctx context.Context) ([]githubreposmodels.GithubRepository, error)
+	GetPackagesInfoUpdatedSince(ecosystem string, packageAndVersions map[string]string, since time.Time, ctx context.Context) ([]models.ScannedPackage, error)
+	GetAdvisoryDatabaseTimestamp(ctx context.Context) (time.Time, error)
+	GetRepositories(owner string, accessToken string, ctx context.Context) ([]githubreposmodels.GithubRepository, error)
+	GetCommitSha(repositoryUrl string, ref string, accessToken string, ctx context.Context) (string, error)
+	GetTree(repositoryUrl string, sha string, accessToken string, ctx context.Context) (githubreposmodels.GitTree, error)
+	GetBlob(repositoryUrl string, sha string, accessToken string, ctx context.Context) ([]byte, error)
+	GetDependencyGraphSbom(repositoryUrl string, accessToken string, ctx context.Context) (dependencygraphmodels.SpdxDocument, error)
+	GetTokenInfo(accessToken string, ctx context.Context) (authenticaionmodels.TokenInfo, error)
 }
 
+const advisoryTimestampCacheKey = "advisory-database-timestamp"
+
+// githubs max page size
+const repositoriesPerPage = 100
+
+var errOwnerNotFound = errors.New("owner not found")
+
+// ErrTokenUnauthorized github rejected the token, it was revoked, expired or never valid
+var ErrTokenUnauthorized = errors.New("github token is no longer valid")
+
+var errNotAUser = errors.New("token doesnt belong to a user")
+
 type GithubClient struct {
-	client              *http.Client
-	cb                  *gobreaker.CircuitBreaker
-	baseUrl             *url.URL
-	cache               *cache.Cache
-	personalAccessToken *string
-	clientId            *string
+	client      *http.Client
+	cb          *gobreaker.CircuitBreaker
+	baseUrl     *url.URL
+	cache       *cache.Cache
+	tokenSource AdvisoryTokenSource
+	clientId    *string
 }
 
-func NewGithubClient(config *configuration.Config, cache *cache.Cache) (*GithubClient, error) {
+func NewGithubClient(config *configuration.Config, cache *cache.Cache, tokenSource AdvisoryTokenSource) (*GithubClient, error) {
 	client := &http.Client{
 		Timeout: 1 * time.Minute,
 		Transport: &http.Transport{
@@ -61,12 +85,12 @@ func NewGithubClient(config *configuration.Config, cache *cache.Cache) (*GithubC
 	cb := gobreaker.NewCircuitBreaker(cbSettings)
 
 	return &GithubClient{
-		client:              client,
-		cb:                  cb,
-		baseUrl:             baseUrl,
-		cache:               cache,
-		personalAccessToken: &config.GithubClientSettings.PAT,
-		clientId:            &config.GithubClientSettings.ClientId,
+		client:      client,
+		cb:          cb,
+		baseUrl:     baseUrl,
+		cache:       cache,
+		tokenSource: tokenSource,
+		clientId:    &config.GithubClientSettings.ClientId,
 	}, nil
 }
 
@@ -81,13 +105,81 @@ func (c *GithubClient) GetPackagesInfo(ecosystem string, packageAndVersions map[
 		return nil, nil
 	}
 
+	return c.getAdvisoriesOfEachType(url, packageAndVersions, ctx)
+}
+
+func (c *GithubClient) GetPackagesInfoUpdatedSince(ecosystem string, packageAndVersions map[string]string, since time.Time, ctx context.Context) ([]models.ScannedPackage, error) {
+	if len(packageAndVersions) == 0 {
+		return nil, nil
+	}
+
+	query := c.buildPackagesQuery(ecosystem, packageAndVersions)
+	if query == "" {
+		return nil, nil
+	}
+
+	//github only filters on dates so we may get advisories from earlier the same day, which just forces a rescan
+	query = fmt.Sprintf("%s&updated=%s", query, url.QueryEscape(">="+since.UTC().Format("2006-01-02")))
+	return c.getAdvisoriesOfEachType(query, packageAndVersions, ctx)
+}
+
+func (c *GithubClient) GetAdvisoryDatabaseTimestamp(ctx context.Context) (time.Time, error) {
+	response, err := c.cache.GetOrCreate(advisoryTimestampCacheKey, func(entry *cache.CacheEntry) (interface{}, error) {
+		entry.Expiration = time.Now().Add(10 * time.Minute)
+
+		latest, err := c.getAdvisoriesOfEachType(fmt.Sprintf("%sadvisories?sort=updated&direction=desc&per_page=1", c.baseUrl), nil, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		var timestamp time.Time
+		for _, advisory := range latest {
+			if advisory.UpdatedAt.After(timestamp) {
+				timestamp = advisory.UpdatedAt
+			}
+		}
+
+		return timestamp, nil
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error getting advisory database timestamp: %w", err)
+	}
+
+	timestamp, ok := response.(time.Time)
+	if !ok {
+		return time.Time{}, fmt.Errorf("unexpected response type when converting response")
+	}
+
+	return timestamp, nil
+}
+
+// getAdvisoriesOfEachType github filters on a single advisory type per request, so malware needs its own request
+func (c *GithubClient) getAdvisoriesOfEachType(query string, packageAndVersions map[string]string, ctx context.Context) ([]models.ScannedPackage, error) {
+	var result []models.ScannedPackage
+	for _, advisoryType := range advisorytypes.AdvisoryTypes {
+		advisories, err := c.getAdvisories(fmt.Sprintf("%s&type=%s", query, advisoryType), packageAndVersions, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error getting %s advisories: %w", advisoryType, err)
+		}
+		result = append(result, advisories...)
+	}
+
+	return result, nil
+}
+
+func (c *GithubClient) getAdvisories(url string, packageAndVersions map[string]string, ctx context.Context) ([]models.ScannedPackage, error) {
+	accessToken, err := c.tokenSource.AdvisoryToken(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting github token for advisory lookups: %w", err)
+	}
+
 	cbResult, err := c.cb.Execute(func() (interface{}, error) {
 		request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create http request: %w", err)
 		}
 
-		request.Header.Set("Authorization", "bearer "+*c.personalAccessToken)
+		request.Header.Set("Authorization", "bearer "+accessToken)
 
 		response, err := c.client.Do(request)
 		if err != nil {
@@ -130,10 +222,42 @@ func (c *GithubClient) GetPackagesInfo(ecosystem string, packageAndVersions map[
 	return results, nil
 }
 
-func (c *GithubClient) GetRepositories(accessToken string, ctx context.Context) ([]githubreposmodels.GithubRepository, error) {
-	url := fmt.Sprintf("%suser/repos", c.baseUrl)
+// GetRepositories lists every repository the owner has, orgs and users live on different endpoints so we try the org first
+func (c *GithubClient) GetRepositories(owner string, accessToken string, ctx context.Context) ([]githubreposmodels.GithubRepository, error) {
+	repositories, err := c.getAllRepositoryPages(fmt.Sprintf("%sorgs/%s/repos?type=all&per_page=%d", c.baseUrl, url.PathEscape(owner), repositoriesPerPage), accessToken, ctx)
+	if errors.Is(err, errOwnerNotFound) {
+		repositories, err = c.getAllRepositoryPages(fmt.Sprintf("%susers/%s/repos?type=all&per_page=%d", c.baseUrl, url.PathEscape(owner), repositoriesPerPage), accessToken, ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return repositories, nil
+}
+
+// getAllRepositoryPages github hands back the next page in the link header until there isnt one
+func (c *GithubClient) getAllRepositoryPages(url string, accessToken string, ctx context.Context) ([]githubreposmodels.GithubRepository, error) {
+	var result []githubreposmodels.GithubRepository
+	for url != "" {
+		fmt.Printf("\n Repo Url: %s", url)
+		repositories, next, err := c.getRepositoryPage(url, accessToken, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, repositories...)
+		url = next
+	}
+
+	return result, nil
+}
+
+func (c *GithubClient) getRepositoryPage(url string, accessToken string, ctx context.Context) ([]githubreposmodels.GithubRepository, string, error) {
+	type repositoryPage struct {
+		repositories []githubreposmodels.GithubRepository
+		next         string
+	}
 
-	fmt.Printf("\n Repo Url: %s", url)
 	cbResult, cbErr := c.cb.Execute(func() (interface{}, error) {
 		request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 		if err != nil {
@@ -144,7 +268,7 @@ func (c *GithubClient) GetRepositories(accessToken string, ctx context.Context)
 
 		response, err := c.client.Do(request)
 		if err != nil {
-			return nil, fmt.Errorf("client respoded with status %d", response.StatusCode)
+			return nil, fmt.Errorf("client response error: %w", err)
 		}
 		defer response.Body.Close()
 
@@ -153,6 +277,9 @@ func (c *GithubClient) GetRepositories(accessToken string, ctx context.Context)
 			return nil, fmt.Errorf("error reading body from client: %w", err)
 		}
 
+		if response.StatusCode == http.StatusNotFound {
+			return nil, errOwnerNotFound
+		}
 		if response.StatusCode != 200 {
 			return nil, handleGithubClientError(body, response.StatusCode)
 		}
@@ -162,14 +289,136 @@ func (c *GithubClient) GetRepositories(accessToken string, ctx context.Context)
 			return nil, handleGithubClientError(body, response.StatusCode)
 		}
 
-		return result, nil
+		return repositoryPage{repositories: result, next: extensions.NextPageLink(response.Header.Get("Link"))}, nil
 	})
 
 	if cbErr != nil {
-		return nil, cbErr
+		return nil, "", cbErr
+	}
+
+	result, ok := cbResult.(repositoryPage)
+	if !ok {
+		return nil, "", fmt.Errorf("unexpected response type when converting response")
+	}
+
+	return result.repositories, result.next, nil
+}
+
+// GetCommitSha resolves a branch, tag or sha to the commit it points at, an empty ref is the default branch.
+// the sha media type returns just the sha instead of the whole commit
+func (c *GithubClient) GetCommitSha(repositoryUrl string, ref string, accessToken string, ctx context.Context) (string, error) {
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	body, err := c.getContent(fmt.Sprintf("%s/commits/%s", repositoryUrl, ref), "application/vnd.github.sha", accessToken, ctx)
+	if err != nil {
+		return "", fmt.Errorf("error resolving %s for %s: %w", ref, repositoryUrl, err)
+	}
+
+	return strings.TrimSpace(string(body)), nil
+}
+
+// GetTree lists every file at the commit in one request
+func (c *GithubClient) GetTree(repositoryUrl string, sha string, accessToken string, ctx context.Context) (githubreposmodels.GitTree, error) {
+	response, err := c.cache.GetOrCreate("github-tree-"+repositoryUrl+"-"+sha, func(entry *cache.CacheEntry) (interface{}, error) {
+		entry.Expiration = time.Now().Add(10 * time.Minute)
+
+		body, err := c.getContent(fmt.Sprintf("%s/git/trees/%s?recursive=1", repositoryUrl, sha), "application/vnd.github+json", accessToken, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		var tree githubreposmodels.GitTree
+		if err := json.Unmarshal(body, &tree); err != nil {
+			return nil, fmt.Errorf("error unmarshalling git tree: %w", err)
+		}
+
+		return tree, nil
+	})
+	if err != nil {
+		return githubreposmodels.GitTree{}, fmt.Errorf("error getting git tree for %s: %w", repositoryUrl, err)
+	}
+
+	result, ok := response.(githubreposmodels.GitTree)
+	if !ok {
+		return githubreposmodels.GitTree{}, fmt.Errorf("unexpected response type when converting response")
+	}
+
+	return result, nil
+}
+
+// GetBlob the raw media type skips the base64 wrapping github gives blobs by default
+func (c *GithubClient) GetBlob(repositoryUrl string, sha string, accessToken string, ctx context.Context) ([]byte, error) {
+	body, err := c.getContent(fmt.Sprintf("%s/git/blobs/%s", repositoryUrl, sha), "application/vnd.github.raw", accessToken, ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting blob %s: %w", sha, err)
+	}
+
+	return body, nil
+}
+
+// GetDependencyGraphSbom exports the dependency graph github already keeps for the repository as spdx json
+func (c *GithubClient) GetDependencyGraphSbom(repositoryUrl string, accessToken string, ctx context.Context) (dependencygraphmodels.SpdxDocument, error) {
+	response, err := c.cache.GetOrCreate("github-sbom-"+repositoryUrl, func(entry *cache.CacheEntry) (interface{}, error) {
+		entry.Expiration = time.Now().Add(10 * time.Minute)
+
+		body, err := c.getContent(repositoryUrl+"/dependency-graph/sbom", "application/vnd.github+json", accessToken, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		var sbom dependencygraphmodels.SbomResponse
+		if err := json.Unmarshal(body, &sbom); err != nil {
+			return nil, fmt.Errorf("error unmarshalling sbom: %w", err)
+		}
+
+		return sbom.Sbom, nil
+	})
+	if err != nil {
+		return dependencygraphmodels.SpdxDocument{}, fmt.Errorf("error getting dependency graph for %s: %w", repositoryUrl, err)
 	}
 
-	result, ok := cbResult.([]githubreposmodels.GithubRepository)
+	result, ok := response.(dependencygraphmodels.SpdxDocument)
+	if !ok {
+		return dependencygraphmodels.SpdxDocument{}, fmt.Errorf("unexpected response type when converting response")
+	}
+
+	return result, nil
+}
+
+func (c *GithubClient) getContent(url string, accept string, accessToken string, ctx context.Context) ([]byte, error) {
+	cbResult, err := c.cb.Execute(func() (interface{}, error) {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error creating http request %s", err)
+		}
+
+		request.Header.Set("Authorization", "Bearer "+accessToken)
+		request.Header.Set("Accept", accept)
+
+		response, err := c.client.Do(request)
+		if err != nil {
+			return nil, fmt.Errorf("client response error: %w", err)
+		}
+		defer response.Body.Close()
+
+		body, err := io.ReadAll(response.Body)
+		if err != nil {
+			return nil, fmt.Errorf("error reading body from client: %w", err)
+		}
+
+		if response.StatusCode != 200 {
+			return nil, handleGithubClientError(body, response.StatusCode)
+		}
+
+		return body, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result, ok := cbResult.([]byte)
 	if !ok {
 		return nil, fmt.Errorf("unexpected response type when converting response")
 	}
@@ -177,6 +426,92 @@ func (c *GithubClient) GetRepositories(accessToken string, ctx context.Context)
 	return result, nil
 }
 
+// GetTokenInfo reads the user, scopes and rate limit for a token, uncached so auth status is always current
+func (c *GithubClient) GetTokenInfo(accessToken string, ctx context.Context) (authenticaionmodels.TokenInfo, error) {
+	body, header, err := c.getWithHeaders(fmt.Sprintf("%srate_limit", c.baseUrl), accessToken, ctx)
+	if err != nil {
+		return authenticaionmodels.TokenInfo{}, err
+	}
+
+	var rateLimit authenticaionmodels.RateLimitResponse
+	if err := json.Unmarshal(body, &rateLimit); err != nil {
+		return authenticaionmodels.TokenInfo{}, fmt.Errorf("error unmarshalling rate limit: %w", err)
+	}
+
+	result := authenticaionmodels.TokenInfo{RateLimit: rateLimit.Resources.Core}
+	for _, scope := range strings.Split(header.Get("X-OAuth-Scopes"), ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			result.Scopes = append(result.Scopes, scope)
+		}
+	}
+
+	body, _, err = c.getWithHeaders(fmt.Sprintf("%suser", c.baseUrl), accessToken, ctx)
+	if errors.Is(err, errNotAUser) {
+		return result, nil
+	}
+	if err != nil {
+		return authenticaionmodels.TokenInfo{}, err
+	}
+
+	var user authenticaionmodels.AuthenticatedUser
+	if err := json.Unmarshal(body, &user); err != nil {
+		return authenticaionmodels.TokenInfo{}, fmt.Errorf("error unmarshalling authenticated user: %w", err)
+	}
+	result.Login = user.Login
+
+	return result, nil
+}
+
+func (c *GithubClient) getWithHeaders(url string, accessToken string, ctx context.Context) ([]byte, http.Header, error) {
+	type contentWithHeaders struct {
+		body   []byte
+		header http.Header
+	}
+
+	cbResult, err := c.cb.Execute(func() (interface{}, error) {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error creating http request %s", err)
+		}
+
+		request.Header.Set("Authorization", "Bearer "+accessToken)
+		request.Header.Set("Accept", "application/vnd.github+json")
+
+		response, err := c.client.Do(request)
+		if err != nil {
+			return nil, fmt.Errorf("client response error: %w", err)
+		}
+		defer response.Body.Close()
+
+		body, err := io.ReadAll(response.Body)
+		if err != nil {
+			return nil, fmt.Errorf("error reading body from client: %w", err)
+		}
+
+		switch response.StatusCode {
+		case http.StatusOK:
+			return contentWithHeaders{body: body, header: response.Header}, nil
+		case http.StatusUnauthorized:
+			return nil, ErrTokenUnauthorized
+		case http.StatusForbidden:
+			//installation tokens get a 403 from endpoints that need a user
+			return nil, errNotAUser
+		default:
+			return nil, handleGithubClientError(body, response.StatusCode)
+		}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, ok := cbResult.(contentWithHeaders)
+	if !ok {
+		return nil, nil, fmt.Errorf("unexpected response type when converting response")
+	}
+
+	return result.body, result.header, nil
+}
+
 func (c *GithubClient) buildPackagesQuery(ecosystem string, packages map[string]string) string {
 	baseUrl := fmt.Sprintf("%sadvisories?ecosystem=%s", c.baseUrl, ecosystem)
 	var urlBuilder strings.Builder
@@ -0,0 +1,294 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	cache "github.com/RobsonDevCode/deepscan/internal/caching"
+	graphqlmodels "github.com/RobsonDevCode/deepscan/internal/clients/models/githubGraphql"
+	"github.com/RobsonDevCode/deepscan/internal/configuration"
+	ecosystemconstants "github.com/RobsonDevCode/deepscan/internal/scanner/constants/ecosystem"
+	"github.com/sony/gobreaker"
+)
+
+// how many packages we alias into a single query, each alias can return up to 100 nodes
+const graphqlAliasesPerQuery = 50
+
+const securityVulnerabilityFields = `nodes {
+      advisory {
+        ghsaId classification summary description severity publishedAt updatedAt withdrawnAt permalink
+        identifiers { type value }
+        cvss { score vectorString }
+        cwes(first: 10) { nodes { cweId name } }
+      }
+      package { name ecosystem }
+      vulnerableVersionRange
+      firstPatchedVersion { identifier }
+    }
+    pageInfo { hasNextPage endCursor }`
+
+type GithubGraphqlClientService interface {
+	GetSecurityVulnerabilities(ecosystem string, packageNames []string, ctx context.Context) ([]graphqlmodels.SecurityVulnerability, error)
+	GetAdvisoryDatabaseTimestamp(ctx context.Context) (time.Time, error)
+}
+
+type GithubGraphqlClient struct {
+	client      *http.Client
+	cb          *gobreaker.CircuitBreaker
+	baseUrl     *url.URL
+	cache       *cache.Cache
+	tokenSource AdvisoryTokenSource
+
+	mu        sync.Mutex
+	totalCost int
+}
+
+func NewGithubGraphqlClient(config *configuration.Config, cache *cache.Cache, tokenSource AdvisoryTokenSource) (*GithubGraphqlClient, error) {
+	client := &http.Client{
+		Timeout: 1 * time.Minute,
+		Transport: &http.Transport{
+			MaxIdleConns:        100,
+			MaxIdleConnsPerHost: 10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+
+	cbSettings := gobreaker.Settings{
+		Name:        "git-graphql-client",
+		MaxRequests: 5,
+		Interval:    3 * time.Second,
+		Timeout:     20 * time.Second,
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= 5
+		},
+		OnStateChange: func(name string, from gobreaker.State, to gobreaker.State) {
+			fmt.Printf("Circuit breaker state changed from %v to %v\n", from, to)
+		},
+	}
+
+	baseUrl, err := url.Parse(config.GithubClientSettings.BaseUrl)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing base url to a url type, %w", err)
+	}
+
+	return &GithubGraphqlClient{
+		client:      client,
+		cb:          gobreaker.NewCircuitBreaker(cbSettings),
+		baseUrl:     baseUrl,
+		cache:       cache,
+		tokenSource: tokenSource,
+	}, nil
+}
+
+// GetSecurityVulnerabilities aliases one securityVulnerabilities field per package so a whole batch is a single POST,
+// packages with more than one page are re-queried with their cursor until every page is read
+func (c *GithubGraphqlClient) GetSecurityVulnerabilities(ecosystem string, packageNames []string, ctx context.Context) ([]graphqlmodels.SecurityVulnerability, error) {
+	graphqlEcosystem, err := toGraphqlEcosystem(ecosystem)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []graphqlmodels.SecurityVulnerability
+	for start := 0; start < len(packageNames); start += graphqlAliasesPerQuery {
+		end := min(start+graphqlAliasesPerQuery, len(packageNames))
+
+		vulnerabilities, err := c.queryPackages(graphqlEcosystem, packageNames[start:end], ctx)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, vulnerabilities...)
+	}
+
+	return result, nil
+}
+
+func (c *GithubGraphqlClient) GetAdvisoryDatabaseTimestamp(ctx context.Context) (time.Time, error) {
+	response, err := c.cache.GetOrCreate("graphql-"+advisoryTimestampCacheKey, func(entry *cache.CacheEntry) (interface{}, error) {
+		entry.Expiration = time.Now().Add(10 * time.Minute)
+
+		data, err := c.execute(graphqlmodels.GraphqlRequest{
+			Query: `query { rateLimit { cost limit remaining resetAt } latest: securityAdvisories(first: 1, classifications: [GENERAL, MALWARE], orderBy: {field: UPDATED_AT, direction: DESC}) { nodes { updatedAt } } }`,
+		}, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		var latest struct {
+			Nodes []graphqlmodels.SecurityAdvisory `json:"nodes"`
+		}
+		if err := json.Unmarshal(data["latest"], &latest); err != nil {
+			return nil, fmt.Errorf("error unmarshalling latest advisory: %w", err)
+		}
+
+		if len(latest.Nodes) == 0 {
+			return time.Time{}, nil
+		}
+
+		return latest.Nodes[0].UpdatedAt, nil
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error getting advisory database timestamp: %w", err)
+	}
+
+	timestamp, ok := response.(time.Time)
+	if !ok {
+		return time.Time{}, fmt.Errorf("unexpected response type when converting response")
+	}
+
+	return timestamp, nil
+}
+
+func (c *GithubGraphqlClient) queryPackages(graphqlEcosystem string, packageNames []string, ctx context.Context) ([]graphqlmodels.SecurityVulnerability, error) {
+	cursors := make(map[int]string)
+	pending := make([]int, len(packageNames))
+	for i := range packageNames {
+		pending[i] = i
+	}
+
+	var result []graphqlmodels.SecurityVulnerability
+	for len(pending) > 0 {
+		request := buildSecurityVulnerabilitiesQuery(graphqlEcosystem, packageNames, pending, cursors)
+
+		data, err := c.execute(request, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		var nextPending []int
+		for _, index := range pending {
+			var connection graphqlmodels.SecurityVulnerabilityConnection
+			if err := json.Unmarshal(data[fmt.Sprintf("p%d", index)], &connection); err != nil {
+				return nil, fmt.Errorf("error unmarshalling vulnerabilities for %s: %w", packageNames[index], err)
+			}
+
+			result = append(result, connection.Nodes...)
+			if connection.PageInfo.HasNextPage {
+				cursors[index] = connection.PageInfo.EndCursor
+				nextPending = append(nextPending, index)
+			}
+		}
+
+		pending = nextPending
+	}
+
+	return result, nil
+}
+
+func buildSecurityVulnerabilitiesQuery(graphqlEcosystem string, packageNames []string, indexes []int, cursors map[int]string) graphqlmodels.GraphqlRequest {
+	variables := make(map[string]interface{})
+	var declarations []string
+	var fields strings.Builder
+
+	for _, index := range indexes {
+		declarations = append(declarations, fmt.Sprintf("$p%d: String!", index))
+		variables[fmt.Sprintf("p%d", index)] = packageNames[index]
+
+		after := ""
+		if cursor, ok := cursors[index]; ok {
+			declarations = append(declarations, fmt.Sprintf("$c%d: String", index))
+			variables[fmt.Sprintf("c%d", index)] = cursor
+			after = fmt.Sprintf(", after: $c%d", index)
+		}
+
+		fmt.Fprintf(&fields, "  p%d: securityVulnerabilities(ecosystem: %s, package: $p%d, classifications: [GENERAL, MALWARE], first: 100%s) {\n    %s\n  }\n",
+			index, graphqlEcosystem, index, after, securityVulnerabilityFields)
+	}
+
+	query := fmt.Sprintf("query(%s) {\n  rateLimit { cost limit remaining resetAt }\n%s}", strings.Join(declarations, ", "), fields.String())
+	return graphqlmodels.GraphqlRequest{
+		Query:     query,
+		Variables: variables,
+	}
+}
+
+func (c *GithubGraphqlClient) execute(graphqlRequest graphqlmodels.GraphqlRequest, ctx context.Context) (map[string]json.RawMessage, error) {
+	payload, err := json.Marshal(graphqlRequest)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling graphql request: %w", err)
+	}
+
+	accessToken, err := c.tokenSource.AdvisoryToken(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting github token for advisory lookups: %w", err)
+	}
+
+	cbResult, err := c.cb.Execute(func() (interface{}, error) {
+		request, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%sgraphql", c.baseUrl), bytes.NewBuffer(payload))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create http request: %w", err)
+		}
+
+		request.Header.Set("Authorization", "bearer "+accessToken)
+		request.Header.Set("Content-Type", "application/json")
+
+		response, err := c.client.Do(request)
+		if err != nil {
+			return nil, fmt.Errorf("client response error: %w", err)
+		}
+		defer response.Body.Close()
+
+		body, err := io.ReadAll(response.Body)
+		if err != nil {
+			return nil, fmt.Errorf("could not read body from client request %w", err)
+		}
+		if response.StatusCode != 200 {
+			return nil, handleGithubClientError(body, response.StatusCode)
+		}
+
+		var result graphqlmodels.GraphqlResponse
+		if err := json.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("error unmarshalling graphql response: %w", err)
+		}
+
+		if len(result.Errors) > 0 {
+			return nil, fmt.Errorf("graphql error %s: %s", result.Errors[0].Type, result.Errors[0].Message)
+		}
+
+		return result.Data, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data, ok := cbResult.(map[string]json.RawMessage)
+	if !ok {
+		return nil, fmt.Errorf("unexpected response type when converting response")
+	}
+
+	c.reportRateLimit(data)
+	return data, nil
+}
+
+func (c *GithubGraphqlClient) reportRateLimit(data map[string]json.RawMessage) {
+	var rateLimit graphqlmodels.RateLimit
+	if err := json.Unmarshal(data["rateLimit"], &rateLimit); err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	c.totalCost += rateLimit.Cost
+	total := c.totalCost
+	c.mu.Unlock()
+
+	fmt.Printf("\nGraphQL rate limit: query cost %d, run total %d, %d/%d remaining until %s",
+		rateLimit.Cost, total, rateLimit.Remaining, rateLimit.Limit, rateLimit.ResetAt.Local().Format("15:04"))
+}
+
+func toGraphqlEcosystem(ecosystem string) (string, error) {
+	switch ecosystem {
+	case ecosystemconstants.Npm:
+		return "NPM", nil
+	case ecosystemconstants.Nuget:
+		return "NUGET", nil
+	default:
+		return "", fmt.Errorf("ecosystem %s not supported by github graphql", ecosystem)
+	}
+}
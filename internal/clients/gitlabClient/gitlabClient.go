@@ -0,0 +1,318 @@
+package gitlabclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	cache "github.com/RobsonDevCode/deepscan/internal/caching"
+	gitlabmodels "github.com/RobsonDevCode/deepscan/internal/clients/models/gitlab"
+	"github.com/RobsonDevCode/deepscan/internal/configuration"
+	"github.com/sony/gobreaker"
+)
+
+// gitlabs max page size
+const projectsPerPage = 100
+
+const tokenEnvironmentVariable = "GITLAB_TOKEN"
+
+type GitlabClientService interface {
+	GetGroupProjects(baseUrl string, group string, ctx context.Context) ([]gitlabmodels.GitlabProject, error)
+	GetCommitSha(projectUrl string, ref string, ctx context.Context) (string, error)
+	GetRepositoryTree(projectUrl string, sha string, ctx context.Context) ([]gitlabmodels.GitlabTreeEntry, error)
+	GetBlob(projectUrl string, sha string, ctx context.Context) ([]byte, error)
+	GitCredentials() (string, string, error)
+}
+
+type GitlabClient struct {
+	client              *http.Client
+	cb                  *gobreaker.CircuitBreaker
+	cache               *cache.Cache
+	personalAccessToken *string
+}
+
+func NewGitlabClient(config *configuration.Config, cache *cache.Cache) *GitlabClient {
+	client := &http.Client{
+		Timeout: 1 * time.Minute,
+		Transport: &http.Transport{
+			MaxIdleConns:        100,
+			MaxIdleConnsPerHost: 10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+
+	cbSettings := gobreaker.Settings{
+		Name:        "gitlab-client",
+		MaxRequests: 5,
+		Interval:    3 * time.Second,
+		Timeout:     20 * time.Second,
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= 5
+		},
+		OnStateChange: func(name string, from gobreaker.State, to gobreaker.State) {
+			fmt.Printf("Circuit breaker state changed from %v to %v\n", from, to)
+		},
+	}
+
+	return &GitlabClient{
+		client:              client,
+		cb:                  gobreaker.NewCircuitBreaker(cbSettings),
+		cache:               cache,
+		personalAccessToken: &config.GitlabClientSettings.PAT,
+	}
+}
+
+// GetGroupProjects lists every project in the group and its subgroups, following the pages gitlab splits them over
+func (c *GitlabClient) GetGroupProjects(baseUrl string, group string, ctx context.Context) ([]gitlabmodels.GitlabProject, error) {
+	response, err := c.cache.GetOrCreate("gitlab-projects-"+baseUrl+group, func(entry *cache.CacheEntry) (interface{}, error) {
+		entry.Expiration = time.Now().Add(10 * time.Minute)
+
+		var result []gitlabmodels.GitlabProject
+		page := "1"
+		for page != "" {
+			query := fmt.Sprintf("%sapi/v4/groups/%s/projects?include_subgroups=true&archived=false&per_page=%d&page=%s",
+				withTrailingSlash(baseUrl), url.PathEscape(group), projectsPerPage, page)
+
+			projects, nextPage, err := c.getProjectsPage(query, ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, projects...)
+			page = nextPage
+		}
+
+		return result, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting gitlab projects for %s: %w", group, err)
+	}
+
+	result, ok := response.([]gitlabmodels.GitlabProject)
+	if !ok {
+		return nil, fmt.Errorf("unexpected response type when converting response")
+	}
+
+	return result, nil
+}
+
+func (c *GitlabClient) getProjectsPage(query string, ctx context.Context) ([]gitlabmodels.GitlabProject, string, error) {
+	type projectsPage struct {
+		projects []gitlabmodels.GitlabProject
+		nextPage string
+	}
+
+	cbResult, err := c.cb.Execute(func() (interface{}, error) {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, query, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create http request: %w", err)
+		}
+
+		token, err := c.token()
+		if err != nil {
+			return nil, err
+		}
+		request.Header.Set("PRIVATE-TOKEN", token)
+
+		response, err := c.client.Do(request)
+		if err != nil {
+			return nil, fmt.Errorf("client response error: %w", err)
+		}
+		defer response.Body.Close()
+
+		body, err := io.ReadAll(response.Body)
+		if err != nil {
+			return nil, fmt.Errorf("could not read body from client request %w", err)
+		}
+
+		if response.StatusCode != 200 {
+			return nil, handleGitlabClientError(body, response.StatusCode)
+		}
+
+		var projects []gitlabmodels.GitlabProject
+		if err := json.Unmarshal(body, &projects); err != nil {
+			return nil, fmt.Errorf("error unmarshalling gitlab projects: %w", err)
+		}
+
+		return projectsPage{projects: projects, nextPage: response.Header.Get("X-Next-Page")}, nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	page, ok := cbResult.(projectsPage)
+	if !ok {
+		return nil, "", fmt.Errorf("unexpected response type when converting response")
+	}
+
+	return page.projects, page.nextPage, nil
+}
+
+// GetCommitSha resolves a branch, tag or sha to the commit it points at, an empty ref is the default branch
+func (c *GitlabClient) GetCommitSha(projectUrl string, ref string, ctx context.Context) (string, error) {
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	body, _, err := c.get(fmt.Sprintf("%s/repository/commits/%s", projectUrl, url.PathEscape(ref)), ctx)
+	if err != nil {
+		return "", fmt.Errorf("error resolving %s for %s: %w", ref, projectUrl, err)
+	}
+
+	var commit struct {
+		Id string `json:"id"`
+	}
+	if err := json.Unmarshal(body, &commit); err != nil {
+		return "", fmt.Errorf("error unmarshalling gitlab commit: %w", err)
+	}
+
+	return commit.Id, nil
+}
+
+// GetRepositoryTree lists every file at the commit, the tree is paged the same way projects are
+func (c *GitlabClient) GetRepositoryTree(projectUrl string, sha string, ctx context.Context) ([]gitlabmodels.GitlabTreeEntry, error) {
+	response, err := c.cache.GetOrCreate("gitlab-tree-"+projectUrl+"-"+sha, func(entry *cache.CacheEntry) (interface{}, error) {
+		entry.Expiration = time.Now().Add(10 * time.Minute)
+
+		var result []gitlabmodels.GitlabTreeEntry
+		page := "1"
+		for page != "" {
+			query := fmt.Sprintf("%s/repository/tree?recursive=true&ref=%s&per_page=%d&page=%s", projectUrl, sha, projectsPerPage, page)
+
+			body, nextPage, err := c.get(query, ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			var entries []gitlabmodels.GitlabTreeEntry
+			if err := json.Unmarshal(body, &entries); err != nil {
+				return nil, fmt.Errorf("error unmarshalling gitlab tree: %w", err)
+			}
+
+			result = append(result, entries...)
+			page = nextPage
+		}
+
+		return result, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting gitlab tree for %s: %w", projectUrl, err)
+	}
+
+	result, ok := response.([]gitlabmodels.GitlabTreeEntry)
+	if !ok {
+		return nil, fmt.Errorf("unexpected response type when converting response")
+	}
+
+	return result, nil
+}
+
+func (c *GitlabClient) GetBlob(projectUrl string, sha string, ctx context.Context) ([]byte, error) {
+	body, _, err := c.get(fmt.Sprintf("%s/repository/blobs/%s/raw", projectUrl, sha), ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting blob %s: %w", sha, err)
+	}
+
+	return body, nil
+}
+
+func (c *GitlabClient) get(query string, ctx context.Context) ([]byte, string, error) {
+	type page struct {
+		body     []byte
+		nextPage string
+	}
+
+	cbResult, err := c.cb.Execute(func() (interface{}, error) {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, query, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create http request: %w", err)
+		}
+
+		token, err := c.token()
+		if err != nil {
+			return nil, err
+		}
+		request.Header.Set("PRIVATE-TOKEN", token)
+
+		response, err := c.client.Do(request)
+		if err != nil {
+			return nil, fmt.Errorf("client response error: %w", err)
+		}
+		defer response.Body.Close()
+
+		body, err := io.ReadAll(response.Body)
+		if err != nil {
+			return nil, fmt.Errorf("could not read body from client request %w", err)
+		}
+
+		if response.StatusCode != 200 {
+			return nil, handleGitlabClientError(body, response.StatusCode)
+		}
+
+		return page{body: body, nextPage: response.Header.Get("X-Next-Page")}, nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	result, ok := cbResult.(page)
+	if !ok {
+		return nil, "", fmt.Errorf("unexpected response type when converting response")
+	}
+
+	return result.body, result.nextPage, nil
+}
+
+// GitCredentials gitlab takes a personal access token as the password for the oauth2 user when cloning over https
+func (c *GitlabClient) GitCredentials() (string, string, error) {
+	token, err := c.token()
+	if err != nil {
+		return "", "", err
+	}
+
+	return "oauth2", token, nil
+}
+
+// token prefers the environment so ci runners dont need the token written to configuration
+func (c *GitlabClient) token() (string, error) {
+	if token := os.Getenv(tokenEnvironmentVariable); token != "" {
+		return token, nil
+	}
+
+	if *c.personalAccessToken == "" || strings.HasPrefix(*c.personalAccessToken, "{") {
+		return "", fmt.Errorf("no gitlab token found, set %s or gitlab_client_settings.personal_access_token", tokenEnvironmentVariable)
+	}
+
+	return *c.personalAccessToken, nil
+}
+
+func handleGitlabClientError(body []byte, statusCode int) error {
+	var gitlabError struct {
+		Message interface{} `json:"message"`
+		Error   string      `json:"error"`
+	}
+
+	if err := json.Unmarshal(body, &gitlabError); err != nil || (gitlabError.Message == nil && gitlabError.Error == "") {
+		return fmt.Errorf("gitlab client response error status: %d", statusCode)
+	}
+
+	if gitlabError.Error != "" {
+		return fmt.Errorf("gitlab client response error status: %d, %s", statusCode, gitlabError.Error)
+	}
+
+	return fmt.Errorf("gitlab client response error status: %d, %v", statusCode, gitlabError.Message)
+}
+
+func withTrailingSlash(baseUrl string) string {
+	if strings.HasSuffix(baseUrl, "/") {
+		return baseUrl
+	}
+
+	return baseUrl + "/"
+}
@@ -0,0 +1,29 @@
+package mapper
+
+import (
+	azuredevopsmodels "github.com/RobsonDevCode/deepscan/internal/clients/models/azureDevops"
+	cmdmodels "github.com/RobsonDevCode/deepscan/internal/thirdPartyCommands/models"
+)
+
+// MapAzureRepositories repository names are only unique within a project, so we prefix the project when listing several
+func MapAzureRepositories(azureRepos []azuredevopsmodels.GitRepository, acrossProjects bool) []cmdmodels.Repository {
+	result := make([]cmdmodels.Repository, 0)
+
+	for _, azureRepo := range azureRepos {
+		name := azureRepo.Name
+		if acrossProjects {
+			name = azureRepo.Project.Name + "/" + azureRepo.Name
+		}
+
+		repo := cmdmodels.Repository{
+			SSHUrl:     azureRepo.SshUrl,
+			HttpsUrl:   azureRepo.RemoteUrl,
+			ApiUrl:     azureRepo.Url,
+			Name:       name,
+			IsDisabled: azureRepo.IsDisabled,
+		}
+		result = append(result, repo)
+	}
+
+	return result
+}
@@ -0,0 +1,49 @@
+package mapper
+
+import (
+	bitbucketmodels "github.com/RobsonDevCode/deepscan/internal/clients/models/bitbucket"
+	cmdmodels "github.com/RobsonDevCode/deepscan/internal/thirdPartyCommands/models"
+)
+
+// MapBitbucketCloudRepositories cloud has no archiving so every repository is scanned
+func MapBitbucketCloudRepositories(cloudRepos []bitbucketmodels.CloudRepository) []cmdmodels.Repository {
+	result := make([]cmdmodels.Repository, 0)
+
+	for _, cloudRepo := range cloudRepos {
+		repo := cmdmodels.Repository{
+			SSHUrl:     cloneLink(cloudRepo.Links, "ssh"),
+			HttpsUrl:   cloneLink(cloudRepo.Links, "https"),
+			Name:       cloudRepo.FullName,
+			IsDisabled: false,
+		}
+		result = append(result, repo)
+	}
+
+	return result
+}
+
+func MapBitbucketDataCenterRepositories(dataCenterRepos []bitbucketmodels.DataCenterRepository) []cmdmodels.Repository {
+	result := make([]cmdmodels.Repository, 0)
+
+	for _, dataCenterRepo := range dataCenterRepos {
+		repo := cmdmodels.Repository{
+			SSHUrl:     cloneLink(dataCenterRepo.Links, "ssh"),
+			HttpsUrl:   cloneLink(dataCenterRepo.Links, "http"),
+			Name:       dataCenterRepo.Project.Key + "/" + dataCenterRepo.Slug,
+			IsDisabled: dataCenterRepo.Archived,
+		}
+		result = append(result, repo)
+	}
+
+	return result
+}
+
+func cloneLink(links bitbucketmodels.Links, name string) string {
+	for _, link := range links.Clone {
+		if link.Name == name {
+			return link.Href
+		}
+	}
+
+	return ""
+}
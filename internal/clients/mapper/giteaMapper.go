@@ -0,0 +1,28 @@
+package mapper
+
+import (
+	giteamodels "github.com/RobsonDevCode/deepscan/internal/clients/models/gitea"
+	cmdmodels "github.com/RobsonDevCode/deepscan/internal/thirdPartyCommands/models"
+)
+
+func MapGiteaRepositories(giteaRepos []giteamodels.GiteaRepository) []cmdmodels.Repository {
+	result := make([]cmdmodels.Repository, 0)
+
+	for _, giteaRepo := range giteaRepos {
+		//nothing to clone so cloning would just fail the scan
+		if giteaRepo.Empty {
+			continue
+		}
+
+		repo := cmdmodels.Repository{
+			SSHUrl:     giteaRepo.SshUrl,
+			HttpsUrl:   giteaRepo.CloneUrl,
+			Topics:     giteaRepo.Topics,
+			Name:       giteaRepo.Name,
+			IsDisabled: giteaRepo.Archived,
+		}
+		result = append(result, repo)
+	}
+
+	return result
+}
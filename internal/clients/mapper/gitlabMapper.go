@@ -0,0 +1,29 @@
+package mapper
+
+import (
+	gitlabmodels "github.com/RobsonDevCode/deepscan/internal/clients/models/gitlab"
+	cmdmodels "github.com/RobsonDevCode/deepscan/internal/thirdPartyCommands/models"
+)
+
+func MapGitlabProjects(gitlabProjects []gitlabmodels.GitlabProject) []cmdmodels.Repository {
+	result := make([]cmdmodels.Repository, 0)
+
+	for _, gitlabProject := range gitlabProjects {
+		//nothing to clone so cloning would just fail the scan
+		if gitlabProject.EmptyRepo || gitlabProject.Archived {
+			continue
+		}
+
+		repo := cmdmodels.Repository{
+			SSHUrl:     gitlabProject.SshUrl,
+			HttpsUrl:   gitlabProject.HttpUrl,
+			ApiUrl:     gitlabProject.Links.Self,
+			Topics:     gitlabProject.Topics,
+			Name:       gitlabProject.Name,
+			IsDisabled: false,
+		}
+		result = append(result, repo)
+	}
+
+	return result
+}
@@ -11,6 +11,9 @@ func Map(githubRepos []githubreposmodels.GithubRepository) []cmdmodels.Repositor
 	for _, githubRepo := range githubRepos {
 		repo := cmdmodels.Repository{
 			SSHUrl:     githubRepo.CloneUrl,
+			HttpsUrl:   githubRepo.CloneUrl,
+			ApiUrl:     githubRepo.Url,
+			Topics:     githubRepo.Topics,
 			Name:       githubRepo.Name,
 			IsDisabled: false,
 		}
@@ -0,0 +1,10 @@
+package azuredevopsmodels
+
+type GitCommitList struct {
+	Value []GitCommit `json:"value"`
+	Count int         `json:"count"`
+}
+
+type GitCommit struct {
+	CommitId string `json:"commitId"`
+}
@@ -0,0 +1,14 @@
+package azuredevopsmodels
+
+type GitItemList struct {
+	Value []GitItem `json:"value"`
+	Count int       `json:"count"`
+}
+
+type GitItem struct {
+	ObjectId      string `json:"objectId"`
+	GitObjectType string `json:"gitObjectType"`
+	//paths start from the repository root e.g. /src/Api/Api.csproj
+	Path     string `json:"path"`
+	IsFolder bool   `json:"isFolder"`
+}
@@ -0,0 +1,19 @@
+package azuredevopsmodels
+
+type GitRepositoryList struct {
+	Value []GitRepository `json:"value"`
+	Count int             `json:"count"`
+}
+
+type GitRepository struct {
+	Name       string         `json:"name"`
+	Url        string         `json:"url"`
+	SshUrl     string         `json:"sshUrl"`
+	RemoteUrl  string         `json:"remoteUrl"`
+	IsDisabled bool           `json:"isDisabled"`
+	Project    ProjectSummary `json:"project"`
+}
+
+type ProjectSummary struct {
+	Name string `json:"name"`
+}
@@ -0,0 +1,38 @@
+package bitbucketmodels
+
+type CloudRepositoryPage struct {
+	Values []CloudRepository `json:"values"`
+	Next   string            `json:"next"`
+}
+
+type CloudRepository struct {
+	FullName string `json:"full_name"`
+	Links    Links  `json:"links"`
+}
+
+type DataCenterRepositoryPage struct {
+	Values        []DataCenterRepository `json:"values"`
+	IsLastPage    bool                   `json:"isLastPage"`
+	NextPageStart int                    `json:"nextPageStart"`
+}
+
+type DataCenterRepository struct {
+	Slug     string            `json:"slug"`
+	Archived bool              `json:"archived"`
+	Project  DataCenterProject `json:"project"`
+	Links    Links             `json:"links"`
+}
+
+type DataCenterProject struct {
+	Key string `json:"key"`
+}
+
+type Links struct {
+	Clone []CloneLink `json:"clone"`
+}
+
+// CloneLink name is https or ssh on cloud and http or ssh on data center
+type CloneLink struct {
+	Name string `json:"name"`
+	Href string `json:"href"`
+}
@@ -0,0 +1,6 @@
+package models
+
+type Cvss struct {
+	Score        float64 `json:"score"`
+	VectorString string  `json:"vector_string"`
+}
@@ -0,0 +1,6 @@
+package models
+
+type Cwe struct {
+	CweId string `json:"cwe_id"`
+	Name  string `json:"name"`
+}
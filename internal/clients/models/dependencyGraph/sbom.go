@@ -0,0 +1,32 @@
+package dependencygraphmodels
+
+type SbomResponse struct {
+	Sbom SpdxDocument `json:"sbom"`
+}
+
+type SpdxDocument struct {
+	SpdxId        string             `json:"SPDXID"`
+	Name          string             `json:"name"`
+	Packages      []SpdxPackage      `json:"packages"`
+	Relationships []SpdxRelationship `json:"relationships"`
+}
+
+type SpdxPackage struct {
+	SpdxId       string            `json:"SPDXID"`
+	Name         string            `json:"name"`
+	VersionInfo  string            `json:"versionInfo"`
+	ExternalRefs []SpdxExternalRef `json:"externalRefs"`
+}
+
+type SpdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	//a purl for package references e.g. pkg:npm/%40babel/core@7.24.0
+	ReferenceLocator string `json:"referenceLocator"`
+}
+
+type SpdxRelationship struct {
+	SpdxElementId      string `json:"spdxElementId"`
+	RelatedSpdxElement string `json:"relatedSpdxElement"`
+	RelationshipType   string `json:"relationshipType"`
+}
@@ -5,4 +5,6 @@ type FailedProjectScan struct {
 	ServiceName string
 	ProjectName string
 	PackageName string
+	Profile     string
+	Provider    string
 }
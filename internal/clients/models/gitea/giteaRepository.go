@@ -0,0 +1,10 @@
+package giteamodels
+
+type GiteaRepository struct {
+	Name     string   `json:"full_name"`
+	SshUrl   string   `json:"ssh_url"`
+	CloneUrl string   `json:"clone_url"`
+	Archived bool     `json:"archived"`
+	Empty    bool     `json:"empty"`
+	Topics   []string `json:"topics"`
+}
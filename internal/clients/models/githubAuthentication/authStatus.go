@@ -0,0 +1,9 @@
+package authenticaionmodels
+
+type AuthStatus struct {
+	Method   string
+	LoggedIn bool
+	//false when github rejected the stored token
+	Valid     bool
+	TokenInfo TokenInfo
+}
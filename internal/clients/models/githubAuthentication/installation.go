@@ -0,0 +1,10 @@
+package authenticaionmodels
+
+type Installation struct {
+	Id      int64               `json:"id"`
+	Account InstallationAccount `json:"account"`
+}
+
+type InstallationAccount struct {
+	Login string `json:"login"`
+}
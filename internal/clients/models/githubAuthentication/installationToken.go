@@ -0,0 +1,8 @@
+package authenticaionmodels
+
+import "time"
+
+type InstallationToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,28 @@
+package authenticaionmodels
+
+// TokenInfo what github reports about a token, installation tokens arent a user so Login is empty for them
+type TokenInfo struct {
+	Login string
+	//only classic oauth tokens report scopes, fine grained and installation tokens leave this empty
+	Scopes    []string
+	RateLimit RateLimit
+}
+
+type RateLimitResponse struct {
+	Resources RateLimitResources `json:"resources"`
+}
+
+type RateLimitResources struct {
+	Core RateLimit `json:"core"`
+}
+
+type RateLimit struct {
+	Limit     int   `json:"limit"`
+	Remaining int   `json:"remaining"`
+	Used      int   `json:"used"`
+	Reset     int64 `json:"reset"`
+}
+
+type AuthenticatedUser struct {
+	Login string `json:"login"`
+}
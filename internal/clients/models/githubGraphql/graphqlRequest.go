@@ -0,0 +1,20 @@
+package graphqlmodels
+
+import "encoding/json"
+
+type GraphqlRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+type GraphqlResponse struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []GraphqlError             `json:"errors"`
+}
+
+type GraphqlError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+	//list indexes in the path are numbers, the rest are field names
+	Path []any `json:"path"`
+}
@@ -0,0 +1,10 @@
+package graphqlmodels
+
+import "time"
+
+type RateLimit struct {
+	Cost      int       `json:"cost"`
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	ResetAt   time.Time `json:"resetAt"`
+}
@@ -0,0 +1,63 @@
+package graphqlmodels
+
+import "time"
+
+type SecurityVulnerabilityConnection struct {
+	Nodes    []SecurityVulnerability `json:"nodes"`
+	PageInfo PageInfo                `json:"pageInfo"`
+}
+
+type PageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+type SecurityVulnerability struct {
+	Advisory               SecurityAdvisory     `json:"advisory"`
+	Package                SecurityPackage      `json:"package"`
+	VulnerableVersionRange string               `json:"vulnerableVersionRange"`
+	FirstPatchedVersion    *FirstPatchedVersion `json:"firstPatchedVersion"`
+}
+
+type SecurityPackage struct {
+	Name      string `json:"name"`
+	Ecosystem string `json:"ecosystem"`
+}
+
+type FirstPatchedVersion struct {
+	Identifier string `json:"identifier"`
+}
+
+type SecurityAdvisory struct {
+	GhsaId         string                `json:"ghsaId"`
+	Classification string                `json:"classification"`
+	Summary        string                `json:"summary"`
+	Description    string                `json:"description"`
+	Severity       string                `json:"severity"`
+	PublishedAt    time.Time             `json:"publishedAt"`
+	UpdatedAt      time.Time             `json:"updatedAt"`
+	WithdrawnAt    *time.Time            `json:"withdrawnAt"`
+	Permalink      string                `json:"permalink"`
+	Identifiers    []AdvisoryIdentifier  `json:"identifiers"`
+	Cvss           *AdvisoryCvss         `json:"cvss"`
+	Cwes           AdvisoryCweConnection `json:"cwes"`
+}
+
+type AdvisoryCvss struct {
+	Score        float64 `json:"score"`
+	VectorString string  `json:"vectorString"`
+}
+
+type AdvisoryCweConnection struct {
+	Nodes []AdvisoryCwe `json:"nodes"`
+}
+
+type AdvisoryCwe struct {
+	CweId string `json:"cweId"`
+	Name  string `json:"name"`
+}
+
+type AdvisoryIdentifier struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
@@ -0,0 +1,16 @@
+package gitlabmodels
+
+type GitlabProject struct {
+	Name          string   `json:"path_with_namespace"`
+	SshUrl        string   `json:"ssh_url_to_repo"`
+	HttpUrl       string   `json:"http_url_to_repo"`
+	DefaultBranch string   `json:"default_branch"`
+	Archived      bool     `json:"archived"`
+	EmptyRepo     bool     `json:"empty_repo"`
+	Topics        []string `json:"topics"`
+	Links         Links    `json:"_links"`
+}
+
+type Links struct {
+	Self string `json:"self"`
+}
@@ -0,0 +1,8 @@
+package gitlabmodels
+
+type GitlabTreeEntry struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Path string `json:"path"`
+}
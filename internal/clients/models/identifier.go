@@ -0,0 +1,6 @@
+package models
+
+type Identifier struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
@@ -0,0 +1,27 @@
+package osvmodels
+
+import "time"
+
+type QueryBatchRequest struct {
+	Queries []Query `json:"queries"`
+}
+
+type Query struct {
+	Package   Package `json:"package"`
+	Version   string  `json:"version,omitempty"`
+	PageToken string  `json:"page_token,omitempty"`
+}
+
+type QueryBatchResponse struct {
+	Results []QueryResult `json:"results"`
+}
+
+type QueryResult struct {
+	Vulns         []VulnerabilityReference `json:"vulns"`
+	NextPageToken string                   `json:"next_page_token"`
+}
+
+type VulnerabilityReference struct {
+	Id       string    `json:"id"`
+	Modified time.Time `json:"modified"`
+}
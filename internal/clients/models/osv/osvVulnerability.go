@@ -0,0 +1,32 @@
+package osvmodels
+
+import "time"
+
+type Vulnerability struct {
+	Id               string           `json:"id"`
+	Summary          string           `json:"summary"`
+	Details          string           `json:"details"`
+	Aliases          []string         `json:"aliases"`
+	Modified         time.Time        `json:"modified"`
+	Published        time.Time        `json:"published"`
+	Withdrawn        *time.Time       `json:"withdrawn,omitempty"`
+	Severity         []Severity       `json:"severity"`
+	Affected         []Affected       `json:"affected"`
+	References       []Reference      `json:"references"`
+	DatabaseSpecific DatabaseSpecific `json:"database_specific"`
+}
+
+type Severity struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+type Reference struct {
+	Type string `json:"type"`
+	Url  string `json:"url"`
+}
+
+type DatabaseSpecific struct {
+	Severity string   `json:"severity"`
+	CweIds   []string `json:"cwe_ids"`
+}
@@ -0,0 +1,12 @@
+package models
+
+// ProjectDiff findings for one project compared between two refs, each package carries a single vulnerability
+type ProjectDiff struct {
+	ServiceName   string
+	Name          string
+	BaseCommitSha string
+	HeadCommitSha string
+	Added         []ScannedPackage
+	Removed       []ScannedPackage
+	Unchanged     []ScannedPackage
+}
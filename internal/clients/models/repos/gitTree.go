@@ -0,0 +1,15 @@
+package githubreposmodels
+
+type GitTree struct {
+	Sha  string         `json:"sha"`
+	Tree []GitTreeEntry `json:"tree"`
+	//github stops at 100,000 entries or 7MB
+	Truncated bool `json:"truncated"`
+}
+
+type GitTreeEntry struct {
+	Path string `json:"path"`
+	Type string `json:"type"`
+	Sha  string `json:"sha"`
+	Size int64  `json:"size"`
+}
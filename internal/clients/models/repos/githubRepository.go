@@ -1,7 +1,14 @@
 package githubreposmodels
 
 type GithubRepository struct {
-	Name     string `json:"full_name"`
-	Private  bool   `json:"private"`
-	CloneUrl string `json:"clone_url"`
+	Name       string   `json:"full_name"`
+	Url        string   `json:"url"`
+	Private    bool     `json:"private"`
+	CloneUrl   string   `json:"clone_url"`
+	SshUrl     string   `json:"ssh_url"`
+	Archived   bool     `json:"archived"`
+	Fork       bool     `json:"fork"`
+	Visibility string   `json:"visibility"`
+	Topics     []string `json:"topics"`
+	Language   string   `json:"language"`
 }
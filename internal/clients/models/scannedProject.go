@@ -6,10 +6,28 @@ type ScannedPackage struct {
 	ServiceName      string          `json:"-"`
 	Name             string          `json:"-"`
 	ProjectName      string          `json:"-"`
+	CommitSha        string          `json:"-"`
+	Profile          string          `json:"-"`
+	Provider         string          `json:"-"`
+	GhsaId           string          `json:"ghsa_id"`
+	CveId            string          `json:"cve_id"`
+	Type             string          `json:"type"`
+	Identifiers      []Identifier    `json:"identifiers"`
+	HtmlUrl          string          `json:"html_url"`
+	Aliases          []string        `json:"aliases"`
+	Source           string          `json:"source"`
 	Summary          string          `json:"summary"`
 	Description      string          `json:"description"`
 	Severity         string          `json:"severity"`
+	Cvss             *Cvss           `json:"cvss"`
+	Cwes             []Cwe           `json:"cwes"`
 	GithubReviewedAt time.Time       `json:"github_reviewed_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+	PublishedAt      time.Time       `json:"published_at"`
+	WithdrawnAt      *time.Time      `json:"withdrawn_at"`
 	Vulnerabilities  []Vulnerability `json:"vulnerabilities"`
 	RiskScore        int             `json:"-"`
+	Epss             float64         `json:"-"`
+	KnownExploited   bool            `json:"-"`
+	AdvisoryDatabase string          `json:"-"`
 }
@@ -1,9 +1,19 @@
 package models
 
+import "time"
+
 type ScannerResponse struct {
 	Packages       []ScannedPackage
 	Framework      string
 	Name           string
 	ServiceName    string
 	CurrentVersion string
+	//where findings came from and how fresh that data was, so reports can be audited later
+	AdvisorySource       string
+	AdvisoryDatabaseDate time.Time
+	//the commit the manifests were read from, empty for local directories
+	CommitSha string
+	//the setup profile and its provider, empty outside of profile scans
+	Profile  string
+	Provider string
 }
@@ -6,4 +6,5 @@ type Vulnerability struct {
 	Package                Package `json:"package"`
 	VulnerableVersionRange string  `json:"vulnerable_version_range"`
 	FirstPatchedVersion    string  `json:"first_patched_version"`
+	Direct                 bool    `json:"-"`
 }
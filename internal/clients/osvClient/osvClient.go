@@ -0,0 +1,204 @@
+package osvclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	cache "github.com/RobsonDevCode/deepscan/internal/caching"
+	osvmodels "github.com/RobsonDevCode/deepscan/internal/clients/models/osv"
+	"github.com/RobsonDevCode/deepscan/internal/configuration"
+	"github.com/sony/gobreaker"
+)
+
+type OsvClientService interface {
+	QueryBatch(queries []osvmodels.Query, ctx context.Context) ([]osvmodels.QueryResult, error)
+	GetVulnerability(id string, ctx context.Context) (osvmodels.Vulnerability, error)
+}
+
+type OsvClient struct {
+	client  *http.Client
+	cb      *gobreaker.CircuitBreaker
+	baseUrl *url.URL
+	cache   *cache.Cache
+}
+
+func NewOsvClient(config *configuration.Config, cache *cache.Cache) (*OsvClient, error) {
+	client := &http.Client{
+		Timeout: 1 * time.Minute,
+		Transport: &http.Transport{
+			MaxIdleConns:        100,
+			MaxIdleConnsPerHost: 10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+
+	cbSettings := gobreaker.Settings{
+		Name:        "osv-client",
+		MaxRequests: 5,
+		Interval:    3 * time.Second,
+		Timeout:     20 * time.Second,
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= 5
+		},
+		OnStateChange: func(name string, from gobreaker.State, to gobreaker.State) {
+			fmt.Printf("Circuit breaker state changed from %v to %v\n", from, to)
+		},
+	}
+
+	baseUrl, err := url.Parse(config.OsvClientSettings.BaseUrl)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing base url to a url type, %w", err)
+	}
+
+	cb := gobreaker.NewCircuitBreaker(cbSettings)
+	return &OsvClient{
+		client:  client,
+		cb:      cb,
+		baseUrl: baseUrl,
+		cache:   cache,
+	}, nil
+}
+
+// QueryBatch returns one result per query, following page tokens until every query is complete
+func (c *OsvClient) QueryBatch(queries []osvmodels.Query, ctx context.Context) ([]osvmodels.QueryResult, error) {
+	// page tokens are written onto the queries so we work on a copy, the callers queries are left as they were
+	queries = slices.Clone(queries)
+	results := make([]osvmodels.QueryResult, len(queries))
+
+	pending := make([]int, len(queries))
+	for i := range queries {
+		pending[i] = i
+	}
+
+	for len(pending) > 0 {
+		request := osvmodels.QueryBatchRequest{}
+		for _, index := range pending {
+			request.Queries = append(request.Queries, queries[index])
+		}
+
+		response, err := c.postQueryBatch(request, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(response.Results) != len(pending) {
+			return nil, fmt.Errorf("osv returned %d results for %d queries", len(response.Results), len(pending))
+		}
+
+		var nextPending []int
+		for i, result := range response.Results {
+			index := pending[i]
+			results[index].Vulns = append(results[index].Vulns, result.Vulns...)
+
+			if result.NextPageToken != "" {
+				queries[index].PageToken = result.NextPageToken
+				nextPending = append(nextPending, index)
+			}
+		}
+
+		pending = nextPending
+	}
+
+	return results, nil
+}
+
+func (c *OsvClient) GetVulnerability(id string, ctx context.Context) (osvmodels.Vulnerability, error) {
+	response, err := c.cache.GetOrCreate("osv-"+id, func(entry *cache.CacheEntry) (interface{}, error) {
+		cbResult, err := c.cb.Execute(func() (interface{}, error) {
+			request, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%sv1/vulns/%s", c.baseUrl, url.PathEscape(id)), nil)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create http request: %w", err)
+			}
+
+			body, err := c.send(request)
+			if err != nil {
+				return nil, err
+			}
+
+			var vulnerability osvmodels.Vulnerability
+			if err := json.Unmarshal(body, &vulnerability); err != nil {
+				return nil, fmt.Errorf("error unmarshalling osv vulnerability %s: %w", id, err)
+			}
+
+			return vulnerability, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return cbResult, nil
+	})
+	if err != nil {
+		return osvmodels.Vulnerability{}, fmt.Errorf("error getting osv vulnerability %s: %w", id, err)
+	}
+
+	vulnerability, ok := response.(osvmodels.Vulnerability)
+	if !ok {
+		return osvmodels.Vulnerability{}, fmt.Errorf("unexpected response type when converting response")
+	}
+
+	return vulnerability, nil
+}
+
+func (c *OsvClient) postQueryBatch(queryBatch osvmodels.QueryBatchRequest, ctx context.Context) (osvmodels.QueryBatchResponse, error) {
+	payload, err := json.Marshal(queryBatch)
+	if err != nil {
+		return osvmodels.QueryBatchResponse{}, fmt.Errorf("error marshalling osv query batch: %w", err)
+	}
+
+	cbResult, err := c.cb.Execute(func() (interface{}, error) {
+		request, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%sv1/querybatch", c.baseUrl), bytes.NewBuffer(payload))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create http request: %w", err)
+		}
+		request.Header.Set("Content-Type", "application/json")
+
+		body, err := c.send(request)
+		if err != nil {
+			return nil, err
+		}
+
+		var result osvmodels.QueryBatchResponse
+		if err := json.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("error unmarshalling osv query batch response: %w", err)
+		}
+
+		return result, nil
+	})
+	if err != nil {
+		return osvmodels.QueryBatchResponse{}, err
+	}
+
+	result, ok := cbResult.(osvmodels.QueryBatchResponse)
+	if !ok {
+		return osvmodels.QueryBatchResponse{}, fmt.Errorf("unexpected response type when converting response")
+	}
+
+	return result, nil
+}
+
+func (c *OsvClient) send(request *http.Request) ([]byte, error) {
+	response, err := c.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("client response error: %w", err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("could not read body from client request %w", err)
+	}
+
+	if response.StatusCode != 200 {
+		return nil, fmt.Errorf("osv client response error status: %d, %s", response.StatusCode, string(body))
+	}
+
+	return body, nil
+}
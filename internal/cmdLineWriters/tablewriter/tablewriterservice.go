@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"slices"
+	"strconv"
 
 	"github.com/RobsonDevCode/deepscan/internal/clients/models"
 	"github.com/RobsonDevCode/deepscan/internal/constants/tableHeaders"
@@ -22,6 +23,16 @@ func DisplayPackagesTable(packages []models.ScannedPackage) {
 	}
 
 	fmt.Printf("\nHave %d at Display", len(packages))
+	malware := slices.DeleteFunc(slices.Clone(packages), func(pkg models.ScannedPackage) bool {
+		return !extensions.IsMalware(pkg)
+	})
+	packages = slices.DeleteFunc(slices.Clone(packages), extensions.IsMalware)
+	displayMalwareTable(malware)
+
+	if len(packages) == 0 {
+		return
+	}
+
 	slices.SortFunc(packages, func(a, b models.ScannedPackage) int {
 		return b.RiskScore - a.RiskScore
 	})
@@ -41,22 +52,33 @@ func DisplayPackagesTable(packages []models.ScannedPackage) {
 		}),
 	)
 
-	table.Header(tableHeaders.ExcelPackageTableHeaders)
+	table.Header(tableHeaders.DisplayPackageTableHeaders)
 
 	vulnerablityCount := 0
+	links := make(map[string]string)
+	var linkOrder []string
 	for _, pkg := range packages {
+		advisoryId := extensions.AdvisoryId(pkg)
+		if _, exists := links[advisoryId]; !exists && pkg.HtmlUrl != "" {
+			links[advisoryId] = pkg.HtmlUrl
+			linkOrder = append(linkOrder, advisoryId)
+		}
+
 		for i := range pkg.Vulnerabilities {
 			vulnerablityCount++
 			table.Append([]string{
+				extensions.FormatProfile(pkg.Profile, pkg.Provider),
 				pkg.ServiceName,
-				"",
 				pkg.Vulnerabilities[i].Package.Name,
 				pkg.Vulnerabilities[i].CurrentVersion,
+				advisoryId,
 				extensions.TruncateString(pkg.Summary, 50),
-				extensions.TruncateString(pkg.Description, 50),
 				pkg.Severity,
+				extensions.FormatCvss(pkg),
+				strconv.Itoa(pkg.RiskScore),
 				pkg.Vulnerabilities[i].FirstPatchedVersion,
-				pkg.GithubReviewedAt.Format("2006-01-02"),
+				extensions.FormatDate(pkg.GithubReviewedAt),
+				pkg.AdvisoryDatabase,
 			})
 		}
 
@@ -66,6 +88,59 @@ func DisplayPackagesTable(packages []models.ScannedPackage) {
 	fmt.Printf("\n%s\n", color.HiMagentaString("Download table to see full results"))
 
 	table.Render()
+
+	//links are printed outside the table, wrapping them in a cell breaks them so terminals can't open them
+	fmt.Print("\n Advisories: \n")
+	for _, advisoryId := range linkOrder {
+		fmt.Printf(" %s: %s\n", advisoryId, links[advisoryId])
+	}
+}
+
+// displayMalwareTable malicious packages need removing not upgrading so they get their own section above the vulnerabilities
+func displayMalwareTable(packages []models.ScannedPackage) {
+	if len(packages) == 0 {
+		return
+	}
+
+	red := color.New(color.FgRed, color.Bold)
+	table := tablewriter.NewTable(os.Stdout,
+		tablewriter.WithRenderer(renderer.NewBlueprint(tw.Rendition{
+			Settings: tw.Settings{Separators: tw.Separators{BetweenRows: tw.On}},
+		})),
+		tablewriter.WithConfig(tablewriter.Config{
+			Row: tw.CellConfig{
+				Formatting: tw.CellFormatting{
+					AutoWrap:  tw.WrapNormal,
+					MergeMode: tw.MergeHierarchical}, //wrap long content like summary and discription
+				Alignment:    tw.CellAlignment{Global: tw.AlignCenter},
+				ColMaxWidths: tw.CellWidth{Global: 10},
+			},
+		}),
+	)
+
+	table.Header(tableHeaders.DisplayMalwareTableHeaders)
+	for _, pkg := range packages {
+		for _, vulnerability := range pkg.Vulnerabilities {
+			table.Append([]string{
+				red.Sprint(extensions.FormatProfile(pkg.Profile, pkg.Provider)),
+				red.Sprint(pkg.ServiceName),
+				red.Sprint(vulnerability.Package.Name),
+				red.Sprint(vulnerability.CurrentVersion),
+				red.Sprint(extensions.AdvisoryId(pkg)),
+				red.Sprint(extensions.TruncateString(pkg.Summary, 50)),
+				red.Sprint(pkg.AdvisoryDatabase),
+			})
+		}
+	}
+
+	red.Printf("\n Found %d Malicious Packages, remove these and rotate any secrets the affected projects can reach: \n", extensions.CountMalware(packages))
+	table.Render()
+
+	for _, pkg := range packages {
+		if pkg.HtmlUrl != "" {
+			red.Printf(" %s: %s\n", extensions.AdvisoryId(pkg), pkg.HtmlUrl)
+		}
+	}
 }
 
 func DisplayFailedScanTable(failedScans []models.FailedProjectScan) {
@@ -89,11 +164,12 @@ func DisplayFailedScanTable(failedScans []models.FailedProjectScan) {
 		}),
 	)
 
-	table.Header([]string{"Service Name", "Project Name", "Error"})
+	table.Header([]string{"Profile", "Service Name", "Project Name", "Error"})
 
 	for _, failedScan := range failedScans {
 
 		table.Append([]string{
+			extensions.FormatProfile(failedScan.Profile, failedScan.Provider),
 			failedScan.ServiceName,
 			failedScan.ProjectName,
 			extensions.TruncateString(failedScan.Error.Error(), 500),
@@ -127,12 +203,75 @@ func DisplayInfomationTable(scannedProjects []models.ScannerResponse) {
 			needsUpgrade = "True" // has to be string so we can represent it the table
 		}
 
-		table.Header([]string{"Project", "Framework", "NeedsUpdating"})
+		table.Header([]string{"Profile", "Project", "Framework", "NeedsUpdating", "Advisory Database", "Commit"})
 		table.Append([]string{
+			extensions.FormatProfile(project.Profile, project.Provider),
 			project.Name,
 			project.Framework,
-			needsUpgrade})
+			needsUpgrade,
+			extensions.FormatAdvisoryDatabase(project),
+			extensions.ShortCommitSha(project.CommitSha)})
 	}
 
 	table.Render()
 }
+
+// DisplayDiffTable one table per project, added findings first as theyre what a reviewer needs to act on
+func DisplayDiffTable(diffs []models.ProjectDiff) {
+	if len(diffs) == 0 {
+		fmt.Print(color.GreenString("\n No projects found at either ref\n"))
+		return
+	}
+
+	for _, diff := range diffs {
+		fmt.Printf("\n %s %s..%s: %s added, %s removed, %d unchanged\n",
+			color.CyanString("%s", diff.Name),
+			extensions.ShortCommitSha(diff.BaseCommitSha),
+			extensions.ShortCommitSha(diff.HeadCommitSha),
+			color.RedString("%d", len(diff.Added)),
+			color.GreenString("%d", len(diff.Removed)),
+			len(diff.Unchanged))
+
+		if len(diff.Added)+len(diff.Removed)+len(diff.Unchanged) == 0 {
+			continue
+		}
+
+		table := tablewriter.NewTable(os.Stdout,
+			tablewriter.WithRenderer(renderer.NewBlueprint(tw.Rendition{
+				Settings: tw.Settings{Separators: tw.Separators{BetweenRows: tw.On}},
+			})),
+			tablewriter.WithConfig(tablewriter.Config{
+				Row: tw.CellConfig{
+					Formatting: tw.CellFormatting{
+						AutoWrap:  tw.WrapNormal,
+						MergeMode: tw.MergeHierarchical}, //wrap long content like summary and discription
+					Alignment:    tw.CellAlignment{Global: tw.AlignCenter},
+					ColMaxWidths: tw.CellWidth{Global: 10},
+				},
+			}),
+		)
+
+		table.Header(tableHeaders.DisplayDiffTableHeaders)
+		appendDiffRows(table, color.RedString("Added"), diff.Added)
+		appendDiffRows(table, color.GreenString("Removed"), diff.Removed)
+		appendDiffRows(table, "Unchanged", diff.Unchanged)
+
+		table.Render()
+	}
+}
+
+func appendDiffRows(table *tablewriter.Table, change string, findings []models.ScannedPackage) {
+	for _, finding := range findings {
+		for _, vulnerability := range finding.Vulnerabilities {
+			table.Append([]string{
+				change,
+				vulnerability.Package.Name,
+				vulnerability.CurrentVersion,
+				extensions.AdvisoryId(finding),
+				extensions.TruncateString(finding.Summary, 50),
+				finding.Severity,
+				vulnerability.FirstPatchedVersion,
+			})
+		}
+	}
+}
@@ -11,7 +11,21 @@ const FilePath = "configuration/configuration.yaml"
 
 type Config struct {
 	GithubClientSettings               GithubClientSettings               `yaml:"github_client_settings"`
+	GithubRepositoryFilters            GithubRepositoryFilters            `yaml:"github_repository_filters"`
 	GithubAuthenticationClientSettings GithubAuthenticationClientSettings `yaml:"github_auth_client_settings"`
+	GithubAppSettings                  GithubAppSettings                  `yaml:"github_app_settings"`
+	OsvClientSettings                  OsvClientSettings                  `yaml:"osv_client_settings"`
+	GitlabClientSettings               GitlabClientSettings               `yaml:"gitlab_client_settings"`
+	BitbucketClientSettings            BitbucketClientSettings            `yaml:"bitbucket_client_settings"`
+	AzureDevopsClientSettings          AzureDevopsClientSettings          `yaml:"azure_devops_client_settings"`
+	GiteaClientSettings                GiteaClientSettings                `yaml:"gitea_client_settings"`
+	RiskScoreSettings                  RiskScoreSettings                  `yaml:"risk_score_settings"`
+	RepositoryMirrorSettings           RepositoryMirrorSettings           `yaml:"repository_mirror_settings"`
+	CredentialStoreSettings            CredentialStoreSettings            `yaml:"credential_store_settings"`
+	//ref to scan per repository name e.g. payments-api: release/* or latest-tag, anything missing scans --ref or the default branch
+	RepositoryRefs map[string]string `yaml:"repository_refs"`
+	//ssh or https, https clones with the same token the provider client uses
+	CloneProtocol string `yaml:"clone_protocol"`
 }
 
 type GithubClientSettings struct {
@@ -20,11 +34,91 @@ type GithubClientSettings struct {
 	ClientId string `yaml:"client_id"`
 }
 
+// GithubRepositoryFilters narrows down the repositories scan --all picks up for a github profile
+type GithubRepositoryFilters struct {
+	IncludeArchived bool `yaml:"include_archived"`
+	IncludeForks    bool `yaml:"include_forks"`
+	//all, public, private or internal
+	Visibility string `yaml:"visibility"`
+	//a repository needs at least one of these topics, empty allows any
+	Topics []string `yaml:"topics"`
+	//primary languages to keep, empty allows any
+	Languages []string `yaml:"languages"`
+}
+
 type GithubAuthenticationClientSettings struct {
 	BaseUrl  string `yaml:"base_url"`
 	ClientId string `yaml:"client_id"`
 }
 
+// GithubAppSettings when app_id and private_key_file are set github authenticates as the app instead of the device flow
+type GithubAppSettings struct {
+	//defaults to the github client base url, point it at a stub to test the token exchange
+	BaseUrl        string `yaml:"base_url"`
+	AppId          string `yaml:"app_id"`
+	PrivateKeyFile string `yaml:"private_key_file"`
+	//owner whose installation token advisory lookups use, empty uses the apps first installation
+	AdvisoryOwner string `yaml:"advisory_owner"`
+}
+
+type OsvClientSettings struct {
+	BaseUrl string `yaml:"base_url"`
+}
+
+type GitlabClientSettings struct {
+	PAT string `yaml:"personal_access_token"`
+}
+
+type AzureDevopsClientSettings struct {
+	PAT string `yaml:"personal_access_token"`
+}
+
+// GiteaClientSettings forgejo is a fork of gitea with the same api so it shares these settings
+type GiteaClientSettings struct {
+	PAT string `yaml:"personal_access_token"`
+}
+
+// BitbucketClientSettings takes either an http access token or a username and app password
+type BitbucketClientSettings struct {
+	CloudBaseUrl string `yaml:"cloud_base_url"`
+	Username     string `yaml:"username"`
+	AppPassword  string `yaml:"app_password"`
+	AccessToken  string `yaml:"access_token"`
+}
+
+type RiskScoreSettings struct {
+	Weights RiskScoreWeights `yaml:"weights"`
+	//FIRST epss csv export, cve,epss,percentile
+	EpssFile string `yaml:"epss_file"`
+	//CISA known exploited vulnerabilities json feed
+	KevFile            string            `yaml:"kev_file"`
+	DefaultCriticality string            `yaml:"default_criticality"`
+	Criticality        map[string]string `yaml:"repository_criticality"`
+}
+
+type RiskScoreWeights struct {
+	Cvss        float64 `yaml:"cvss"`
+	Epss        float64 `yaml:"epss"`
+	Kev         float64 `yaml:"kev"`
+	Depth       float64 `yaml:"depth"`
+	Criticality float64 `yaml:"criticality"`
+}
+
+type RepositoryMirrorSettings struct {
+	Enabled bool `yaml:"enabled"`
+	// Directory defaults to deepscan/mirrors under the users cache directory
+	Directory string `yaml:"directory"`
+	MaxSizeMb int64  `yaml:"max_size_mb"`
+}
+
+// CredentialStoreSettings tokens deepscan is given are encrypted with a key file unless DEEPSCAN_CREDENTIAL_PASSPHRASE is set
+type CredentialStoreSettings struct {
+	// File defaults to deepscan/credentials.enc under the users config directory
+	File string `yaml:"file"`
+	// KeyFile defaults to credentials.key next to the file, created on first use
+	KeyFile string `yaml:"key_file"`
+}
+
 func Load() (*Config, error) {
 	data, err := os.ReadFile(FilePath)
 	if err != nil {
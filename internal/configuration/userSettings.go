@@ -4,9 +4,28 @@ import (
 	authenticaionmodels "github.com/RobsonDevCode/deepscan/internal/clients/models/githubAuthentication"
 )
 
+// UserProfiles every provider set up with deepscan setup keyed by the name it was given
+type UserProfiles struct {
+	//used when scan is run without --profile, the first profile set up unless changed
+	DefaultProfile string                   `json:"default_profile"`
+	Profiles       map[string]UsersSettings `json:"profiles"`
+	//plain text tokens from older versions, moved into the credential store the first time they are read
+	AccessToken *authenticaionmodels.GithubAccessToken `json:"access_token,omitempty"`
+	Selections  map[string]RepositorySelection         `json:"selections,omitempty"`
+}
+
 type UsersSettings struct {
-	OrganizationUrl string                                 `json:"org_url"`
-	Profile         string                                 `json:"profile"`
-	Provider        string                                 `json:"provider"`
-	AccessToken     *authenticaionmodels.GithubAccessToken `json:"access_token"`
+	//the profiles name, filled in from its key when read
+	Name            string `json:"-"`
+	OrganizationUrl string `json:"org_url"`
+	Profile         string `json:"profile"`
+	Provider        string `json:"provider"`
+}
+
+// RepositorySelection include and exclude are regexes matched against the repository name,
+// a repository needs at least one of the topics when any are given
+type RepositorySelection struct {
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+	Topics  []string `json:"topics,omitempty"`
 }
@@ -0,0 +1,29 @@
+package advisorysources
+
+var AdvisorySourceOptions = []string{
+	Github,
+	Osv,
+	Both,
+}
+
+var GithubApiOptions = []string{
+	Rest,
+	Graphql,
+}
+
+const (
+	Rest    = "rest"
+	Graphql = "graphql"
+)
+
+const (
+	Github = "github"
+	Osv    = "osv"
+	Both   = "both"
+	//set by --offline rather than offered as a source
+	Offline = "offline"
+)
+
+// DependencyGraph reads packages from githubs dependency graph sbom rather than a clone,
+// it can be paired with an advisory source e.g. dependency-graph,osv
+const DependencyGraph = "dependency-graph"
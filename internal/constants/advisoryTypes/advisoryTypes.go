@@ -0,0 +1,10 @@
+package advisorytypes
+
+// github only returns reviewed advisories unless we ask for a type
+var AdvisoryTypes = []string{
+	Reviewed,
+	Malware,
+}
+
+const Reviewed = "reviewed"
+const Malware = "malware"
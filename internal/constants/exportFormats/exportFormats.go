@@ -0,0 +1,13 @@
+package exportformats
+
+const (
+	Xlsx = "xlsx"
+	Json = "json"
+)
+
+var Formats = []string{
+	Xlsx,
+	Json,
+}
+
+const DefaultDirectory = "./export"
@@ -4,3 +4,10 @@ const (
 	Dotnet = "Dotnet"
 	Npm    = "package-lock.json"
 )
+
+// ManifestPatterns are the only files a scan reads, npm ls needs the package.json next to the lockfile
+var ManifestPatterns = []string{
+	"*.csproj",
+	"package.json",
+	"package-lock.json",
+}
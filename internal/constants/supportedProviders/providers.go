@@ -1,8 +1,15 @@
 package supportedproviders
 
 const (
-	GithubUrl = "https://github.com/"
-	AzureUrl  = "https://dev.azure.com/"
-	Github    = "github"
-	Azure     = "azure"
+	GithubUrl           = "https://github.com/"
+	AzureUrl            = "https://dev.azure.com/"
+	GitlabUrl           = "https://gitlab.com/"
+	BitbucketCloudUrl   = "https://bitbucket.org/"
+	Github              = "github"
+	Azure               = "azure"
+	Gitlab              = "gitlab"
+	BitbucketCloud      = "bitbucket"
+	BitbucketDataCenter = "bitbucket-dc"
+	Gitea               = "gitea"
+	Forgejo             = "forgejo"
 )
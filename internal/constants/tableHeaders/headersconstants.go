@@ -1,5 +1,9 @@
 package tableHeaders
 
-var ExcelPackageTableHeaders = []string{"Service Name", "Project", "Name", "Current Package Version", "Summary", "Description", "Severity", "Patched", "Date Github Updated"}
+var ExcelPackageTableHeaders = []string{"Service Name", "Project", "Name", "Current Package Version", "Advisory", "Type", "CVE", "Summary", "Description", "Severity", "CVSS", "CVSS Vector", "CWEs", "Risk Score", "EPSS", "Known Exploited", "Dependency", "Patched", "Published", "Date Github Updated", "Advisory Database", "Commit", "Profile", "Provider"}
 
-var DisplayPackageTableHeaders = []string{"Service Name", "Name", "Description", "Severity", "Github Updated"}
+var DisplayMalwareTableHeaders = []string{"Profile", "Service Name", "Name", "Current Package Version", "Advisory", "Summary", "Advisory Database"}
+
+var DisplayPackageTableHeaders = []string{"Profile", "Service Name", "Name", "Current Package Version", "Advisory", "Summary", "Severity", "CVSS", "Risk", "Patched", "Date Github Updated", "Advisory Database"}
+
+var DisplayDiffTableHeaders = []string{"Change", "Name", "Current Package Version", "Advisory", "Summary", "Severity", "Patched"}
@@ -0,0 +1,63 @@
+package credentialstore
+
+import (
+	"os"
+)
+
+// names credentials are stored under
+const (
+	Github      = "github"
+	AzureDevops = "azure_devops"
+)
+
+// environmentVariables ci sets these instead of logging in, they win over anything stored
+var environmentVariables = map[string]string{
+	Github: "DEEPSCAN_GITHUB_TOKEN",
+	// same variable the az cli reads so existing pipelines keep working
+	AzureDevops: "AZURE_DEVOPS_EXT_PAT",
+}
+
+type CredentialStoreService interface {
+	// Get an empty secret means nothing is stored under name
+	Get(name string) (string, error)
+	Set(name string, secret string) error
+	Delete(name string) error
+	// Overridden returns the environment variable that replaces the stored secret when it is set
+	Overridden(name string) (string, bool)
+}
+
+// CredentialStore puts the environment in front of an encrypted file
+type CredentialStore struct {
+	file *FileCredentialStore
+}
+
+func NewCredentialStore(file *FileCredentialStore) *CredentialStore {
+	return &CredentialStore{
+		file: file,
+	}
+}
+
+func (s *CredentialStore) Get(name string) (string, error) {
+	if variable, overridden := s.Overridden(name); overridden {
+		return os.Getenv(variable), nil
+	}
+
+	return s.file.Get(name)
+}
+
+func (s *CredentialStore) Set(name string, secret string) error {
+	return s.file.Set(name, secret)
+}
+
+func (s *CredentialStore) Delete(name string) error {
+	return s.file.Delete(name)
+}
+
+func (s *CredentialStore) Overridden(name string) (string, bool) {
+	variable, ok := environmentVariables[name]
+	if !ok {
+		return "", false
+	}
+
+	return variable, os.Getenv(variable) != ""
+}
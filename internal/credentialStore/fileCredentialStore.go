@@ -0,0 +1,307 @@
+package credentialstore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/RobsonDevCode/deepscan/internal/configuration"
+)
+
+const (
+	credentialFolder = "deepscan"
+	credentialFile   = "credentials.enc"
+	keyFile          = "credentials.key"
+
+	passphraseEnvironmentVariable = "DEEPSCAN_CREDENTIAL_PASSPHRASE"
+	// owasps current recommendation for pbkdf2 with sha256
+	pbkdf2Iterations = 600000
+	keySize          = 32
+
+	passphraseKeySource = "passphrase"
+	keyFileKeySource    = "key_file"
+)
+
+// ties the ciphertext to this file format so it cant be replayed as anything else
+var additionalData = []byte("deepscan-credentials-v1")
+
+type encryptedCredentials struct {
+	Version int `json:"version"`
+	// a file encrypted with a passphrase cant be read with the key file and the other way round
+	KeySource  string `json:"key_source"`
+	Salt       []byte `json:"salt,omitempty"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// FileCredentialStore keeps every secret in one AES-GCM encrypted file only the user can read,
+// the key comes from DEEPSCAN_CREDENTIAL_PASSPHRASE when set otherwise from a key file created on first use
+type FileCredentialStore struct {
+	path    string
+	keyPath string
+	mu      sync.Mutex
+}
+
+func NewFileCredentialStore(config *configuration.Config) (*FileCredentialStore, error) {
+	userConfigDir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("error finding user config directory: %w", err)
+	}
+
+	path := config.CredentialStoreSettings.File
+	if path == "" {
+		path = filepath.Join(userConfigDir, credentialFolder, credentialFile)
+	}
+
+	keyPath := config.CredentialStoreSettings.KeyFile
+	if keyPath == "" {
+		keyPath = filepath.Join(filepath.Dir(path), keyFile)
+	}
+
+	return &FileCredentialStore{
+		path:    path,
+		keyPath: keyPath,
+	}, nil
+}
+
+func (s *FileCredentialStore) Get(name string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	secrets, err := s.load()
+	if err != nil {
+		return "", err
+	}
+
+	return secrets[name], nil
+}
+
+func (s *FileCredentialStore) Set(name string, secret string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	secrets, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	secrets[name] = secret
+	return s.save(secrets)
+}
+
+// Delete removes the file once the last secret goes so logging out leaves nothing behind
+func (s *FileCredentialStore) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	secrets, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	delete(secrets, name)
+	if len(secrets) > 0 {
+		return s.save(secrets)
+	}
+
+	if err := os.Remove(s.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error removing credentials %s: %w", s.path, err)
+	}
+
+	return nil
+}
+
+func (s *FileCredentialStore) load() (map[string]string, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return make(map[string]string), nil //nothing stored yet
+		}
+		return nil, fmt.Errorf("error reading credentials %s: %w", s.path, err)
+	}
+
+	var encrypted encryptedCredentials
+	if err := json.Unmarshal(data, &encrypted); err != nil {
+		return nil, fmt.Errorf("error unmarshalling credentials %s: %w", s.path, err)
+	}
+
+	key, err := s.decryptionKey(encrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGcm(key)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := gcm.Open(nil, encrypted.Nonce, encrypted.Ciphertext, additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting credentials %s, the passphrase or key file doesnt match the one they were saved with", s.path)
+	}
+
+	secrets := make(map[string]string)
+	if err := json.Unmarshal(plaintext, &secrets); err != nil {
+		return nil, fmt.Errorf("error unmarshalling decrypted credentials: %w", err)
+	}
+
+	return secrets, nil
+}
+
+// save writes to a temp file first so a failed write cant lose every secret
+func (s *FileCredentialStore) save(secrets map[string]string) error {
+	plaintext, err := json.Marshal(secrets)
+	if err != nil {
+		return fmt.Errorf("error marshalling credentials: %w", err)
+	}
+
+	key, encrypted, err := s.encryptionKey()
+	if err != nil {
+		return err
+	}
+
+	gcm, err := newGcm(key)
+	if err != nil {
+		return err
+	}
+
+	encrypted.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(encrypted.Nonce); err != nil {
+		return fmt.Errorf("error generating nonce: %w", err)
+	}
+	encrypted.Ciphertext = gcm.Seal(nil, encrypted.Nonce, plaintext, additionalData)
+
+	data, err := json.Marshal(encrypted)
+	if err != nil {
+		return fmt.Errorf("error marshalling encrypted credentials: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("error creating credentials directory: %w", err)
+	}
+
+	// WriteFile only applies the mode to new files so a leftover temp file is removed first
+	tmpPath := s.path + ".tmp"
+	os.Remove(tmpPath)
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("error writing credentials %s: %w", tmpPath, err)
+	}
+
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return fmt.Errorf("error writing credentials %s: %w", s.path, err)
+	}
+
+	return nil
+}
+
+func (s *FileCredentialStore) encryptionKey() ([]byte, encryptedCredentials, error) {
+	encrypted := encryptedCredentials{Version: 1}
+
+	if passphrase := os.Getenv(passphraseEnvironmentVariable); passphrase != "" {
+		encrypted.KeySource = passphraseKeySource
+		encrypted.Salt = make([]byte, 16)
+		if _, err := rand.Read(encrypted.Salt); err != nil {
+			return nil, encryptedCredentials{}, fmt.Errorf("error generating salt: %w", err)
+		}
+
+		key, err := pbkdf2.Key(sha256.New, passphrase, encrypted.Salt, pbkdf2Iterations, keySize)
+		if err != nil {
+			return nil, encryptedCredentials{}, fmt.Errorf("error deriving key from passphrase: %w", err)
+		}
+
+		return key, encrypted, nil
+	}
+
+	encrypted.KeySource = keyFileKeySource
+	key, err := s.readKeyFile(true)
+	return key, encrypted, err
+}
+
+func (s *FileCredentialStore) decryptionKey(encrypted encryptedCredentials) ([]byte, error) {
+	switch encrypted.KeySource {
+	case passphraseKeySource:
+		passphrase := os.Getenv(passphraseEnvironmentVariable)
+		if passphrase == "" {
+			return nil, fmt.Errorf("credentials %s were saved with a passphrase, set %s to read them", s.path, passphraseEnvironmentVariable)
+		}
+
+		key, err := pbkdf2.Key(sha256.New, passphrase, encrypted.Salt, pbkdf2Iterations, keySize)
+		if err != nil {
+			return nil, fmt.Errorf("error deriving key from passphrase: %w", err)
+		}
+
+		return key, nil
+
+	case keyFileKeySource:
+		return s.readKeyFile(false)
+
+	default:
+		return nil, fmt.Errorf("credentials %s use an unknown key source %s", s.path, encrypted.KeySource)
+	}
+}
+
+// readKeyFile only creates the key when saving, a missing key while reading means the credentials cant be recovered
+func (s *FileCredentialStore) readKeyFile(create bool) ([]byte, error) {
+	key, err := os.ReadFile(s.keyPath)
+	if errors.Is(err, os.ErrNotExist) && create {
+		return s.createKeyFile()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading credential key %s: %w", s.keyPath, err)
+	}
+
+	if len(key) != keySize {
+		return nil, fmt.Errorf("credential key %s should be %d bytes but is %d", s.keyPath, keySize, len(key))
+	}
+
+	return key, nil
+}
+
+func (s *FileCredentialStore) createKeyFile() ([]byte, error) {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("error generating credential key: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.keyPath), 0700); err != nil {
+		return nil, fmt.Errorf("error creating credential key directory: %w", err)
+	}
+
+	// O_EXCL so two deepscan runs racing to create the key cant each encrypt with a different one
+	file, err := os.OpenFile(s.keyPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if errors.Is(err, os.ErrExist) {
+		return s.readKeyFile(false)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error creating credential key %s: %w", s.keyPath, err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(key); err != nil {
+		return nil, fmt.Errorf("error writing credential key %s: %w", s.keyPath, err)
+	}
+
+	return key, nil
+}
+
+func newGcm(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("error creating cipher: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("error creating gcm: %w", err)
+	}
+
+	return gcm, nil
+}
@@ -0,0 +1,94 @@
+package cvss
+
+import (
+	"math"
+	"strings"
+)
+
+// BaseScore calculates the cvss v3.x base score from a vector string, osv only gives us the vector
+func BaseScore(vector string) (float64, bool) {
+	if !strings.HasPrefix(vector, "CVSS:3.") {
+		return 0, false
+	}
+
+	metrics := make(map[string]string)
+	for _, part := range strings.Split(vector, "/")[1:] {
+		name, value, ok := strings.Cut(part, ":")
+		if ok {
+			metrics[name] = value
+		}
+	}
+
+	scopeChanged := metrics["S"] == "C"
+
+	attackVector, ok1 := lookup(metrics["AV"], map[string]float64{"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2})
+	attackComplexity, ok2 := lookup(metrics["AC"], map[string]float64{"L": 0.77, "H": 0.44})
+	privilegesRequired, ok3 := lookup(metrics["PR"], privilegeWeights(scopeChanged))
+	userInteraction, ok4 := lookup(metrics["UI"], map[string]float64{"N": 0.85, "R": 0.62})
+
+	impactWeights := map[string]float64{"H": 0.56, "L": 0.22, "N": 0}
+	confidentiality, ok5 := lookup(metrics["C"], impactWeights)
+	integrity, ok6 := lookup(metrics["I"], impactWeights)
+	availability, ok7 := lookup(metrics["A"], impactWeights)
+
+	if !(ok1 && ok2 && ok3 && ok4 && ok5 && ok6 && ok7) {
+		return 0, false
+	}
+
+	impactSubScore := 1 - ((1 - confidentiality) * (1 - integrity) * (1 - availability))
+
+	var impact float64
+	if scopeChanged {
+		impact = 7.52*(impactSubScore-0.029) - 3.25*math.Pow(impactSubScore-0.02, 15)
+	} else {
+		impact = 6.42 * impactSubScore
+	}
+
+	if impact <= 0 {
+		return 0, true
+	}
+
+	exploitability := 8.22 * attackVector * attackComplexity * privilegesRequired * userInteraction
+	if scopeChanged {
+		return roundUp(math.Min(1.08*(impact+exploitability), 10)), true
+	}
+
+	return roundUp(math.Min(impact+exploitability, 10)), true
+}
+
+// Severity buckets a score the same way github and nvd do
+func Severity(score float64) string {
+	switch {
+	case score >= 9:
+		return "critical"
+	case score >= 7:
+		return "high"
+	case score >= 4:
+		return "medium"
+	case score > 0:
+		return "low"
+	}
+	return "unknown"
+}
+
+func privilegeWeights(scopeChanged bool) map[string]float64 {
+	if scopeChanged {
+		return map[string]float64{"N": 0.85, "L": 0.68, "H": 0.5}
+	}
+	return map[string]float64{"N": 0.85, "L": 0.62, "H": 0.27}
+}
+
+func lookup(value string, weights map[string]float64) (float64, bool) {
+	weight, ok := weights[value]
+	return weight, ok
+}
+
+// roundUp is the spec's round up to one decimal, done on integers to avoid floating point drift
+func roundUp(value float64) float64 {
+	intInput := int(math.Round(value * 100000))
+	if intInput%10000 == 0 {
+		return float64(intInput) / 100000
+	}
+
+	return (math.Floor(float64(intInput)/10000) + 1) / 10
+}
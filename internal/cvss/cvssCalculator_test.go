@@ -0,0 +1,92 @@
+package cvss
+
+import "testing"
+
+func TestBaseScore(t *testing.T) {
+	// expected scores are the ones nvd publishes for each vector
+	tests := []struct {
+		name     string
+		vector   string
+		expected float64
+	}{
+		{name: "network rce", vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", expected: 9.8},
+		{name: "scope changed rce capped at 10", vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H", expected: 10.0},
+		{name: "reflected xss", vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:R/S:C/C:L/I:L/A:N", expected: 6.1},
+		{name: "stored xss needing a login", vector: "CVSS:3.1/AV:N/AC:L/PR:L/UI:N/S:C/C:L/I:L/A:N", expected: 6.4},
+		{name: "information disclosure", vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:N/A:N", expected: 7.5},
+		{name: "denial of service", vector: "CVSS:3.0/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:H", expected: 7.5},
+		{name: "local privilege escalation", vector: "CVSS:3.1/AV:L/AC:L/PR:L/UI:N/S:U/C:H/I:H/A:H", expected: 7.8},
+		{name: "high complexity rounds up", vector: "CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:U/C:H/I:N/A:N", expected: 5.9},
+		{name: "physical access", vector: "CVSS:3.1/AV:P/AC:H/PR:H/UI:R/S:U/C:L/I:N/A:N", expected: 1.6},
+		{name: "no impact", vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:N", expected: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			score, ok := BaseScore(test.vector)
+			if !ok {
+				t.Fatalf("expected %s to be scored", test.vector)
+			}
+			if score != test.expected {
+				t.Errorf("expected %.1f, got %v", test.expected, score)
+			}
+		})
+	}
+}
+
+func TestBaseScoreRejectsVectors(t *testing.T) {
+	vectors := []string{
+		"AV:N/AC:L/Au:N/C:P/I:P/A:P",
+		"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N",
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H",
+		"CVSS:3.1/AV:X/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+	}
+
+	for _, vector := range vectors {
+		if score, ok := BaseScore(vector); ok {
+			t.Errorf("expected %s to be rejected, got %v", vector, score)
+		}
+	}
+}
+
+func TestRoundUp(t *testing.T) {
+	tests := []struct {
+		value    float64
+		expected float64
+	}{
+		{value: 4.0, expected: 4.0},
+		{value: 4.02, expected: 4.1},
+		{value: 4.000001, expected: 4.0}, // floating point noise isnt rounded up
+		{value: 5.85, expected: 5.9},
+		{value: 9.91, expected: 10.0},
+	}
+
+	for _, test := range tests {
+		if got := roundUp(test.value); got != test.expected {
+			t.Errorf("expected roundUp(%v) to be %v, got %v", test.value, test.expected, got)
+		}
+	}
+}
+
+func TestSeverity(t *testing.T) {
+	tests := []struct {
+		score    float64
+		expected string
+	}{
+		{score: 10, expected: "critical"},
+		{score: 9.0, expected: "critical"},
+		{score: 8.9, expected: "high"},
+		{score: 7.0, expected: "high"},
+		{score: 6.9, expected: "medium"},
+		{score: 4.0, expected: "medium"},
+		{score: 3.9, expected: "low"},
+		{score: 0.1, expected: "low"},
+		{score: 0, expected: "unknown"},
+	}
+
+	for _, test := range tests {
+		if got := Severity(test.score); got != test.expected {
+			t.Errorf("expected %v to be %s, got %s", test.score, test.expected, got)
+		}
+	}
+}
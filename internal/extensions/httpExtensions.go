@@ -0,0 +1,16 @@
+package extensions
+
+import "strings"
+
+// NextPageLink pulls the rel="next" url out of a link header e.g. <https://api.github.com/...&page=2>; rel="next",
+// github and gitea both page this way
+func NextPageLink(linkHeader string) string {
+	for _, link := range strings.Split(linkHeader, ",") {
+		target, rel, ok := strings.Cut(link, ";")
+		if ok && strings.TrimSpace(rel) == `rel="next"` {
+			return strings.Trim(strings.TrimSpace(target), "<>")
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,24 @@
+package extensions
+
+import "testing"
+
+func TestNextPageLink(t *testing.T) {
+	tests := []struct {
+		name       string
+		linkHeader string
+		expected   string
+	}{
+		{name: "no header", linkHeader: "", expected: ""},
+		{name: "next first", linkHeader: `<https://gitea.example.com/api/v1/orgs/acme/repos?page=2>; rel="next", <https://gitea.example.com/api/v1/orgs/acme/repos?page=5>; rel="last"`, expected: "https://gitea.example.com/api/v1/orgs/acme/repos?page=2"},
+		{name: "next last", linkHeader: `<https://gitea.example.com/api/v1/orgs/acme/repos?page=1>; rel="first",<https://gitea.example.com/api/v1/orgs/acme/repos?page=3>; rel="next"`, expected: "https://gitea.example.com/api/v1/orgs/acme/repos?page=3"},
+		{name: "last page", linkHeader: `<https://gitea.example.com/api/v1/orgs/acme/repos?page=1>; rel="first", <https://gitea.example.com/api/v1/orgs/acme/repos?page=4>; rel="prev"`, expected: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := NextPageLink(test.linkHeader); got != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, got)
+			}
+		})
+	}
+}
@@ -1,7 +1,12 @@
 package extensions
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/RobsonDevCode/deepscan/internal/clients/models"
+	advisorytypes "github.com/RobsonDevCode/deepscan/internal/constants/advisoryTypes"
 )
 
 func FlatternPackages(scannedProjects []models.ScannerResponse) []models.ScannedPackage {
@@ -11,8 +16,147 @@ func FlatternPackages(scannedProjects []models.ScannerResponse) []models.Scanned
 		for _, pkg := range scannedProject.Packages {
 			pkg.ServiceName = scannedProject.ServiceName
 			pkg.ProjectName = scannedProject.Name
+			pkg.CommitSha = scannedProject.CommitSha
+			pkg.Profile = scannedProject.Profile
+			pkg.Provider = scannedProject.Provider
+			pkg.AdvisoryDatabase = FormatAdvisoryDatabase(scannedProject)
 			scannedPackages = append(scannedPackages, pkg)
 		}
 	}
 	return scannedPackages
 }
+
+func FormatAdvisoryDatabase(scannedProject models.ScannerResponse) string {
+	if scannedProject.AdvisoryDatabaseDate.IsZero() {
+		return scannedProject.AdvisorySource
+	}
+
+	return fmt.Sprintf("%s %s", scannedProject.AdvisorySource, scannedProject.AdvisoryDatabaseDate.Format("2006-01-02 15:04"))
+}
+
+// FormatProfile e.g. work-azure (azure), empty when the scan didnt come from a profile
+func FormatProfile(profile string, provider string) string {
+	if profile == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("%s (%s)", profile, provider)
+}
+
+// ShortCommitSha the first 7 characters are what git shows and are enough to find the commit
+func ShortCommitSha(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+
+	return sha
+}
+
+// AdvisoryId prefers the ghsa id as thats what github links to, osv only findings fall back to their own id
+func AdvisoryId(pkg models.ScannedPackage) string {
+	if pkg.GhsaId != "" {
+		return pkg.GhsaId
+	}
+
+	if len(pkg.Aliases) > 0 {
+		return pkg.Aliases[0]
+	}
+
+	return pkg.CveId
+}
+
+func FormatCvss(pkg models.ScannedPackage) string {
+	if pkg.Cvss == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%.1f", pkg.Cvss.Score)
+}
+
+func FormatCwes(pkg models.ScannedPackage) string {
+	var cweIds []string
+	for _, cwe := range pkg.Cwes {
+		cweIds = append(cweIds, cwe.CweId)
+	}
+
+	return strings.Join(cweIds, ", ")
+}
+
+func FormatDate(date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+
+	return date.Format("2006-01-02")
+}
+
+// FormatBool the tables are all strings so we spell booleans out the same way the info table does
+func FormatBool(value bool) string {
+	if value {
+		return "True"
+	}
+
+	return "False"
+}
+
+// ExportFileName single scans are named after the service they scanned
+func ExportFileName(packages []models.ScannedPackage, isFullScan bool, extension string) string {
+	name := "full_scan"
+	if !isFullScan && len(packages) > 0 {
+		name = packages[0].ServiceName
+	}
+
+	return fmt.Sprintf("package_%s_vun_%s.%s", name, time.Now().Format("2006-01-02T15-04-05"), extension)
+}
+
+func FormatDependencyDepth(vulnerability models.Vulnerability) string {
+	if vulnerability.Direct {
+		return "Direct"
+	}
+
+	return "Transitive"
+}
+
+func IsMalware(pkg models.ScannedPackage) bool {
+	return pkg.Type == advisorytypes.Malware
+}
+
+// CountMalware counts malicious packages rather than advisories, one advisory can name several packages
+func CountMalware(packages []models.ScannedPackage) int {
+	count := 0
+	for _, pkg := range packages {
+		if IsMalware(pkg) {
+			count += len(pkg.Vulnerabilities)
+		}
+	}
+
+	return count
+}
+
+var severityRanks = map[string]int{
+	"low":      1,
+	"medium":   2,
+	"moderate": 2,
+	"high":     3,
+	"critical": 4,
+}
+
+// SeverityRank orders severities low to critical, malware always ranks as critical and unknown severities rank 0
+func SeverityRank(pkg models.ScannedPackage) int {
+	if IsMalware(pkg) {
+		return severityRanks["critical"]
+	}
+
+	return severityRanks[strings.ToLower(pkg.Severity)]
+}
+
+// IsSeverity checks the name is one SeverityRank knows
+func IsSeverity(severity string) bool {
+	_, ok := severityRanks[strings.ToLower(severity)]
+	return ok
+}
+
+// MeetsSeverity is the package at or above the severity
+func MeetsSeverity(pkg models.ScannedPackage, severity string) bool {
+	return SeverityRank(pkg) >= severityRanks[strings.ToLower(severity)]
+}
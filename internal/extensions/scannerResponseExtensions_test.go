@@ -0,0 +1,41 @@
+package extensions
+
+import (
+	"testing"
+
+	"github.com/RobsonDevCode/deepscan/internal/clients/models"
+	advisorytypes "github.com/RobsonDevCode/deepscan/internal/constants/advisoryTypes"
+)
+
+func TestCountMalware(t *testing.T) {
+	vulnerable := func(count int) []models.Vulnerability {
+		return make([]models.Vulnerability, count)
+	}
+
+	tests := []struct {
+		name     string
+		packages []models.ScannedPackage
+		expected int
+	}{
+		{name: "no findings", expected: 0},
+		{name: "only vulnerabilities", packages: []models.ScannedPackage{{Type: advisorytypes.Reviewed, Vulnerabilities: vulnerable(2)}}, expected: 0},
+		{name: "one malicious package", packages: []models.ScannedPackage{{Type: advisorytypes.Malware, Vulnerabilities: vulnerable(1)}}, expected: 1},
+		{
+			name: "every package an advisory names is counted",
+			packages: []models.ScannedPackage{
+				{Type: advisorytypes.Malware, Vulnerabilities: vulnerable(3)},
+				{Type: advisorytypes.Reviewed, Vulnerabilities: vulnerable(4)},
+				{Type: advisorytypes.Malware, Vulnerabilities: vulnerable(1)},
+			},
+			expected: 4,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := CountMalware(test.packages); got != test.expected {
+				t.Errorf("expected %d malicious packages, got %d", test.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package memoryfs
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FS is a read only fs.FS over files held in memory, manifests fetched from provider apis are written here
+// so the scanner can walk them the same way it walks a clone
+type FS struct {
+	mu    sync.RWMutex
+	files map[string][]byte
+}
+
+func New() *FS {
+	return &FS{files: make(map[string][]byte)}
+}
+
+// WriteFile adds or replaces a file, name uses forward slashes like every fs.FS path
+func (f *FS) WriteFile(name string, data []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.files[path.Clean(name)] = data
+}
+
+func (f *FS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	f.mu.RLock()
+	data, ok := f.files[name]
+	f.mu.RUnlock()
+
+	if ok {
+		return &memoryFile{info: fileInfo{name: path.Base(name), size: int64(len(data))}, reader: bytes.NewReader(data)}, nil
+	}
+
+	entries, err := f.ReadDir(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return &memoryDir{info: fileInfo{name: path.Base(name), dir: true}, entries: entries}, nil
+}
+
+func (f *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	prefix := ""
+	if name != "." {
+		prefix = name + "/"
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	children := make(map[string]fileInfo)
+	for filePath, data := range f.files {
+		if !strings.HasPrefix(filePath, prefix) {
+			continue
+		}
+
+		child, rest, isDir := strings.Cut(strings.TrimPrefix(filePath, prefix), "/")
+		if isDir || rest != "" {
+			children[child] = fileInfo{name: child, dir: true}
+		} else {
+			children[child] = fileInfo{name: child, size: int64(len(data))}
+		}
+	}
+
+	if len(children) == 0 && name != "." {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	entries := make([]fs.DirEntry, 0, len(children))
+	for _, child := range children {
+		entries = append(entries, fs.FileInfoToDirEntry(child))
+	}
+
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+
+	return entries, nil
+}
+
+type fileInfo struct {
+	name string
+	size int64
+	dir  bool
+}
+
+func (i fileInfo) Name() string       { return i.name }
+func (i fileInfo) Size() int64        { return i.size }
+func (i fileInfo) ModTime() time.Time { return time.Time{} }
+func (i fileInfo) IsDir() bool        { return i.dir }
+func (i fileInfo) Sys() any           { return nil }
+
+func (i fileInfo) Mode() fs.FileMode {
+	if i.dir {
+		return fs.ModeDir | 0555
+	}
+	return 0444
+}
+
+type memoryFile struct {
+	info   fileInfo
+	reader *bytes.Reader
+}
+
+func (f *memoryFile) Stat() (fs.FileInfo, error)      { return f.info, nil }
+func (f *memoryFile) Read(buffer []byte) (int, error) { return f.reader.Read(buffer) }
+func (f *memoryFile) Close() error                    { return nil }
+
+type memoryDir struct {
+	info    fileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *memoryDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *memoryDir) Close() error               { return nil }
+
+func (d *memoryDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: fs.ErrInvalid}
+}
+
+func (d *memoryDir) ReadDir(count int) ([]fs.DirEntry, error) {
+	remaining := d.entries[d.offset:]
+	if count <= 0 {
+		d.offset = len(d.entries)
+		return remaining, nil
+	}
+
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+
+	count = min(count, len(remaining))
+	d.offset += count
+	return remaining[:count], nil
+}
@@ -0,0 +1,101 @@
+package repositoryselection
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/RobsonDevCode/deepscan/internal/configuration"
+	cmdmodels "github.com/RobsonDevCode/deepscan/internal/thirdPartyCommands/models"
+)
+
+// Filter keeps the repositories matching any include and no exclude, with no includes every repository is included
+func Filter(repos []cmdmodels.Repository, selection configuration.RepositorySelection) ([]cmdmodels.Repository, error) {
+	includes, err := compile(selection.Include)
+	if err != nil {
+		return nil, err
+	}
+
+	excludes, err := compile(selection.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []cmdmodels.Repository
+	for _, repo := range repos {
+		if len(includes) > 0 && !matchesAny(includes, repo.Name) {
+			continue
+		}
+
+		if matchesAny(excludes, repo.Name) {
+			continue
+		}
+
+		if len(selection.Topics) > 0 && !slices.ContainsFunc(repo.Topics, func(topic string) bool {
+			return slices.ContainsFunc(selection.Topics, func(wanted string) bool { return strings.EqualFold(wanted, topic) })
+		}) {
+			continue
+		}
+
+		result = append(result, repo)
+	}
+
+	return result, nil
+}
+
+// Merge adds the rules from the command line onto a saved selection
+func Merge(saved configuration.RepositorySelection, additional configuration.RepositorySelection) configuration.RepositorySelection {
+	return configuration.RepositorySelection{
+		Include: append(slices.Clone(saved.Include), additional.Include...),
+		Exclude: append(slices.Clone(saved.Exclude), additional.Exclude...),
+		Topics:  append(slices.Clone(saved.Topics), additional.Topics...),
+	}
+}
+
+// FromNames pins a selection to exactly the repositories picked in the prompt
+func FromNames(names []string) configuration.RepositorySelection {
+	var selection configuration.RepositorySelection
+	for _, name := range names {
+		selection.Include = append(selection.Include, "^"+regexp.QuoteMeta(name)+"$")
+	}
+
+	return selection
+}
+
+func IsEmpty(selection configuration.RepositorySelection) bool {
+	return len(selection.Include) == 0 && len(selection.Exclude) == 0 && len(selection.Topics) == 0
+}
+
+func compile(patterns []string) ([]*regexp.Regexp, error) {
+	var result []*regexp.Regexp
+	for _, pattern := range patterns {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid repository pattern %s: %w", pattern, err)
+		}
+		result = append(result, compiled)
+	}
+
+	return result, nil
+}
+
+func matchesAny(patterns []*regexp.Regexp, name string) bool {
+	return slices.ContainsFunc(patterns, func(pattern *regexp.Regexp) bool {
+		return pattern.MatchString(name)
+	})
+}
+
+// Ref finds the ref configured for a repository, by its full name first then just the repository name
+func Ref(refs map[string]string, name string, fallback string) string {
+	if ref, ok := refs[name]; ok {
+		return ref
+	}
+
+	if ref, ok := refs[path.Base(name)]; ok {
+		return ref
+	}
+
+	return fallback
+}
@@ -1,8 +1,12 @@
 package riskscoreconstants
 
+// criticality tags a repository can be given in configuration
 const (
-	Critical = 4
-	High     = 3
-	Medium   = 2
-	Low      = 1
+	Critical = "critical"
+	High     = "high"
+	Medium   = "medium"
+	Low      = "low"
 )
+
+// MaxScore scores are a percentage so they read the same whatever the weights are
+const MaxScore = 100
@@ -1,5 +1,6 @@
 package scannerconstants
 
 const (
-	TempDirctory = "temp"
+	// WorkingDirectoryPattern each run clones into its own os.MkdirTemp directory named after this
+	WorkingDirectoryPattern = "deepscan-*"
 )
@@ -11,11 +11,21 @@ func MapCsProjToProject(csProject *scannermodels.CsProject) scannermodels.Projec
 		Name:               csProject.Name,
 		Ecosystem:          ecosystemconstants.Nuget,
 		PackagesAndVersion: CsProjSliceToMap(*csProject),
+		DirectDependencies: csProjDirectDependencies(*csProject),
 		Framework:          csProject.Framework,
 		Frameworks:         csProject.Frameworks,
 	}
 }
 
+// csProjDirectDependencies we only read package references so every nuget package is direct
+func csProjDirectDependencies(csproj scannermodels.CsProject) map[string]bool {
+	result := make(map[string]bool)
+	for _, pkg := range csproj.PackageReferences {
+		result[pkg.Name] = true
+	}
+	return result
+}
+
 func CsProjSliceToMap(csproj scannermodels.CsProject) map[string]string {
 	result := make(map[string]string)
 	for _, pkg := range csproj.PackageReferences {
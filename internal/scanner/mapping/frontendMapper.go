@@ -14,9 +14,19 @@ func MapNpmResultToProject(response npmmodels.NpmPackageResponse) scannermodels.
 		Name:               response.ServiceName,
 		Ecosystem:          ecosystemconstants.Npm,
 		PackagesAndVersion: MapPackageAndVersion(response.NpmPackage),
+		DirectDependencies: mapDirectDependencies(response.NpmPackage),
 	}
 }
 
+// mapDirectDependencies the top level of npm ls is what package.json asked for
+func mapDirectDependencies(packages map[string]npmmodels.NpmPackage) map[string]bool {
+	result := make(map[string]bool)
+	for pkg := range packages {
+		result[pkg] = true
+	}
+	return result
+}
+
 func MapPackageAndVersion(packages map[string]npmmodels.NpmPackage) map[string]string {
 	result := make(map[string]string)
 	var flattern func(map[string]npmmodels.NpmPackage)
@@ -0,0 +1,95 @@
+package scannermapper
+
+import (
+	"maps"
+	"path"
+	"strings"
+
+	npmmodels "github.com/RobsonDevCode/deepscan/internal/thirdPartyCommands/models/npm"
+)
+
+// MapPackageLock builds the same tree npm ls --all gives us, the top level is what package.json asked for
+// and each dependency is resolved the way node does, nearest node_modules first then up towards the root
+func MapPackageLock(lock npmmodels.PackageLock) npmmodels.NpmPackageResponse {
+	response := npmmodels.NpmPackageResponse{
+		Version:     lock.Version,
+		ServiceName: lock.Name,
+	}
+
+	if len(lock.Packages) == 0 {
+		response.NpmPackage = lock.Dependencies
+		return response
+	}
+
+	root := lock.Packages[""]
+	if response.ServiceName == "" {
+		response.ServiceName = root.Name
+	}
+
+	visited := make(map[string]bool)
+	response.NpmPackage = resolveLockDependencies(lock.Packages, "", requestedDependencies(root), visited)
+	return response
+}
+
+func resolveLockDependencies(packages map[string]npmmodels.PackageLockEntry, from string, requested []string, visited map[string]bool) map[string]npmmodels.NpmPackage {
+	result := make(map[string]npmmodels.NpmPackage)
+
+	for _, name := range requested {
+		installPath, entry, ok := findLockPackage(packages, from, name)
+		if !ok {
+			continue
+		}
+
+		if entry.Link {
+			installPath = entry.Resolved
+			entry = packages[installPath]
+		}
+
+		npmPackage := npmmodels.NpmPackage{Version: entry.Version}
+
+		//deduped in npm ls terms, we already have everything below it
+		if !visited[installPath] {
+			visited[installPath] = true
+			npmPackage.Dependencies = resolveLockDependencies(packages, installPath, requestedDependencies(entry), visited)
+		}
+
+		result[name] = npmPackage
+	}
+
+	return result
+}
+
+func findLockPackage(packages map[string]npmmodels.PackageLockEntry, from string, name string) (string, npmmodels.PackageLockEntry, bool) {
+	for {
+		installPath := path.Join(from, "node_modules", name)
+		if entry, ok := packages[installPath]; ok {
+			return installPath, entry, true
+		}
+
+		if from == "" {
+			return "", npmmodels.PackageLockEntry{}, false
+		}
+
+		index := strings.LastIndex(from, "/node_modules/")
+		if index < 0 {
+			from = ""
+		} else {
+			from = from[:index]
+		}
+	}
+}
+
+func requestedDependencies(entry npmmodels.PackageLockEntry) []string {
+	requested := make(map[string]string)
+	maps.Copy(requested, entry.PeerDependencies)
+	maps.Copy(requested, entry.OptionalDependencies)
+	maps.Copy(requested, entry.DevDependencies)
+	maps.Copy(requested, entry.Dependencies)
+
+	var names []string
+	for name := range requested {
+		names = append(names, name)
+	}
+
+	return names
+}
@@ -0,0 +1,141 @@
+package scannermapper
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	scanresultcache "github.com/RobsonDevCode/deepscan/internal/caching/scanResultCache"
+	dependencygraphmodels "github.com/RobsonDevCode/deepscan/internal/clients/models/dependencyGraph"
+	ecosystemconstants "github.com/RobsonDevCode/deepscan/internal/scanner/constants/ecosystem"
+	scannermodels "github.com/RobsonDevCode/deepscan/internal/scanner/models"
+)
+
+// MapSbomToProjects gives one project per ecosystem in the sbom, github doesnt say which manifest a package came from
+// so the repository is the closest thing we have to a project
+func MapSbomToProjects(serviceName string, sbom dependencygraphmodels.SpdxDocument) []scannermodels.Project {
+	direct := sbomDirectDependencies(sbom)
+	projects := make(map[string]*scannermodels.Project)
+
+	for _, sbomPackage := range sbom.Packages {
+		ecosystem, name, version, ok := parsePackageUrl(sbomPackage)
+		if !ok {
+			continue
+		}
+
+		//packages only declared in a manifest come through as ranges, theres no installed version to check
+		if version == "" || strings.ContainsAny(version, "^~<>=* |") {
+			continue
+		}
+
+		project, exists := projects[ecosystem]
+		if !exists {
+			project = &scannermodels.Project{
+				ServiceName:        serviceName,
+				Name:               fmt.Sprintf("%s (%s)", serviceName, ecosystem),
+				Ecosystem:          ecosystem,
+				PackagesAndVersion: make(map[string]string),
+				DirectDependencies: make(map[string]bool),
+				Repository:         serviceName,
+			}
+			projects[ecosystem] = project
+		}
+
+		project.PackagesAndVersion[name] = version
+		if direct[sbomPackage.SpdxId] {
+			project.DirectDependencies[name] = true
+		}
+	}
+
+	var result []scannermodels.Project
+	for _, project := range projects {
+		project.ManifestHash = hashPackages(project.PackagesAndVersion)
+		result = append(result, *project)
+	}
+
+	return result
+}
+
+// sbomDirectDependencies are whatever the repository itself depends on, the repository is the package the document describes
+func sbomDirectDependencies(sbom dependencygraphmodels.SpdxDocument) map[string]bool {
+	roots := make(map[string]bool)
+	for _, relationship := range sbom.Relationships {
+		if relationship.SpdxElementId == sbom.SpdxId && relationship.RelationshipType == "DESCRIBES" {
+			roots[relationship.RelatedSpdxElement] = true
+		}
+	}
+
+	direct := make(map[string]bool)
+	for _, relationship := range sbom.Relationships {
+		if roots[relationship.SpdxElementId] && relationship.RelationshipType == "DEPENDS_ON" {
+			direct[relationship.RelatedSpdxElement] = true
+		}
+	}
+
+	return direct
+}
+
+// parsePackageUrl reads the purl e.g. pkg:npm/%40babel/core@7.24.0, only ecosystems we can scan are returned
+func parsePackageUrl(sbomPackage dependencygraphmodels.SpdxPackage) (string, string, string, bool) {
+	for _, ref := range sbomPackage.ExternalRefs {
+		if ref.ReferenceType != "purl" {
+			continue
+		}
+
+		purl, ok := strings.CutPrefix(ref.ReferenceLocator, "pkg:")
+		if !ok {
+			continue
+		}
+
+		purl, _, _ = strings.Cut(purl, "#")
+		purl, _, _ = strings.Cut(purl, "?")
+
+		purlType, rest, ok := strings.Cut(purl, "/")
+		if !ok {
+			continue
+		}
+
+		var ecosystem string
+		switch purlType {
+		case "npm":
+			ecosystem = ecosystemconstants.Npm
+		case "nuget":
+			ecosystem = ecosystemconstants.Nuget
+		default:
+			continue
+		}
+
+		name := rest
+		version := sbomPackage.VersionInfo
+		if index := strings.LastIndex(rest, "@"); index > 0 {
+			name = rest[:index]
+			version = rest[index+1:]
+		}
+
+		unescapedName, err := url.PathUnescape(name)
+		if err != nil {
+			continue
+		}
+
+		unescapedVersion, err := url.PathUnescape(version)
+		if err != nil {
+			continue
+		}
+
+		return ecosystem, unescapedName, unescapedVersion, true
+	}
+
+	return "", "", "", false
+}
+
+// hashPackages stands in for the manifest hash so unchanged dependency graphs still hit the scan cache
+func hashPackages(packagesAndVersion map[string]string) string {
+	var lines []string
+	for name, version := range packagesAndVersion {
+		lines = append(lines, name+"@"+version)
+	}
+	slices.Sort(lines)
+
+	return scanresultcache.HashManifest([]byte(strings.Join(lines, "\n")))
+}
@@ -7,4 +7,10 @@ type Project struct {
 	PackagesAndVersion map[string]string
 	Framework          string
 	Frameworks         string
+	ManifestHash       string
+	//packages the project references itself, anything else came in transitively
+	DirectDependencies map[string]bool
+	//directory the repository was cloned into, used to look up the commit it was scanned at
+	Repository string
+	CommitSha  string
 }
@@ -0,0 +1,24 @@
+package scannermodels
+
+type ScanOptions struct {
+	//reuse results for manifests that havent changed since the last run
+	UseCache bool
+	//advisory database findings are looked up in, github, osv or both
+	Source string
+	//rest or graphql, graphql batches large lockfiles into a single request
+	GithubApi string
+	//withdrawn advisories are hidden unless asked for
+	IncludeWithdrawn bool
+	//where packages are read from, empty for manifests or dependency-graph for githubs sbom
+	PackageSource string
+	//branch, tag, sha or pattern e.g. release/* to scan instead of the default branch
+	Ref string
+	//clone with the https url and the providers token rather than ssh
+	CloneOverHttps bool
+	//named profile from deepscan setup, empty for the default profile
+	Profile string
+	//anything that would prompt errors instead, set by --non-interactive or when stdin isnt a terminal
+	NonInteractive bool
+	//commit each repository was scanned at keyed by the directory it was cloned into
+	Commits map[string]string
+}
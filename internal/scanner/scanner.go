@@ -6,18 +6,20 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
-	"github.com/RobsonDevCode/deepscan/internal/clients"
+	scanresultcache "github.com/RobsonDevCode/deepscan/internal/caching/scanResultCache"
 	"github.com/RobsonDevCode/deepscan/internal/clients/models"
 	"github.com/RobsonDevCode/deepscan/internal/extensions"
-	scannerconstants "github.com/RobsonDevCode/deepscan/internal/scanner/constants"
 	ecosystemconstants "github.com/RobsonDevCode/deepscan/internal/scanner/constants/ecosystem"
-	riskscoreconstants "github.com/RobsonDevCode/deepscan/internal/scanner/constants/riskScore"
 	scannermapper "github.com/RobsonDevCode/deepscan/internal/scanner/mapping"
 	scannermodels "github.com/RobsonDevCode/deepscan/internal/scanner/models"
+	advisorysourceservice "github.com/RobsonDevCode/deepscan/internal/services/advisorySourceService"
 	packagereaderservice "github.com/RobsonDevCode/deepscan/internal/services/packageReaderService"
+	riskscoreservice "github.com/RobsonDevCode/deepscan/internal/services/riskScoreService"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -25,33 +27,57 @@ import (
 const batchSize = 100
 
 type ScannerService interface {
-	ScanProject(root string, ctx context.Context) ([]models.ScannerResponse, error)
-	ScanProjects(ctx context.Context) (models.ScanAllResponse, error)
+	ScanProject(root string, options scannermodels.ScanOptions, ctx context.Context) ([]models.ScannerResponse, error)
+	ScanProjects(root string, options scannermodels.ScanOptions, ctx context.Context) (models.ScanAllResponse, error)
+	ScanFileSystem(fsys fs.FS, options scannermodels.ScanOptions, ctx context.Context) (models.ScanAllResponse, error)
+	ScanResolvedProjects(projectFiles []scannermodels.Project, options scannermodels.ScanOptions, ctx context.Context) (models.ScanAllResponse, error)
 }
 
 type Scanner struct {
-	client        clients.GithubClientService
-	packageReader packagereaderservice.PackageReaderService
+	advisorySources advisorysourceservice.AdvisorySourceSelector
+	packageReader   packagereaderservice.PackageReaderService
+	scanResultCache scanresultcache.ScanResultCacheService
+	riskScorer      riskscoreservice.RiskScoreService
 }
 
-func NewScanner(client clients.GithubClientService,
-	packageReader packagereaderservice.PackageReaderService) *Scanner {
+func NewScanner(advisorySources advisorysourceservice.AdvisorySourceSelector,
+	packageReader packagereaderservice.PackageReaderService,
+	scanResultCache scanresultcache.ScanResultCacheService,
+	riskScorer riskscoreservice.RiskScoreService) *Scanner {
 	return &Scanner{
-		client:        client,
-		packageReader: packageReader,
+		advisorySources: advisorySources,
+		packageReader:   packageReader,
+		scanResultCache: scanResultCache,
+		riskScorer:      riskScorer,
 	}
 }
 
-func (s *Scanner) ScanProjects(ctx context.Context) (models.ScanAllResponse, error) {
-	defer os.RemoveAll(scannerconstants.TempDirctory)
-	projectFiles, err := s.GetFilesToScan(scannerconstants.TempDirctory, ctx)
+// ScanProjects scans every repository cloned into root, each top level directory of root is a repository
+func (s *Scanner) ScanProjects(root string, options scannermodels.ScanOptions, ctx context.Context) (models.ScanAllResponse, error) {
+	projectFiles, err := s.GetFilesToScan(root, ctx)
+	if err != nil {
+		return models.ScanAllResponse{}, err
+	}
+
+	return s.ScanResolvedProjects(projectFiles, options, ctx)
+}
+
+// ScanFileSystem scans manifests that were never cloned, each top level directory of fsys is a repository
+func (s *Scanner) ScanFileSystem(fsys fs.FS, options scannermodels.ScanOptions, ctx context.Context) (models.ScanAllResponse, error) {
+	projectFiles, err := s.getFilesFromFileSystem(fsys)
 	if err != nil {
 		return models.ScanAllResponse{}, err
 	}
 
+	return s.ScanResolvedProjects(projectFiles, options, ctx)
+}
+
+// ScanResolvedProjects scans projects whose packages were already read e.g. from a dependency graph
+func (s *Scanner) ScanResolvedProjects(projectFiles []scannermodels.Project, options scannermodels.ScanOptions, ctx context.Context) (models.ScanAllResponse, error) {
 	if projectFiles == nil {
 		return models.ScanAllResponse{}, fmt.Errorf("project files are empty")
 	}
+	setCommits(projectFiles, options)
 
 	//we get 429 and 403 from the github api so we have to put a limiter on the concurrent channels
 	maxConcurrentChans := 2
@@ -62,10 +88,9 @@ func (s *Scanner) ScanProjects(ctx context.Context) (models.ScanAllResponse, err
 	for _, projectFile := range projectFiles {
 		wg.Add(1)
 		go func(pf scannermodels.Project) {
-			var err error
 			defer wg.Done()
 
-			packageInfo, err := s.validateAndScan(pf, &mu, ctx)
+			scannerResponse, err := s.scanProjectFile(pf, options, &mu, ctx)
 			if err != nil {
 				scans <- scannermodels.ConcurrentScanResult{
 					Project:     nil,
@@ -73,27 +98,12 @@ func (s *Scanner) ScanProjects(ctx context.Context) (models.ScanAllResponse, err
 					ServiceName: pf.ServiceName,
 					ProjectName: pf.Name,
 				}
+				return
 			}
-
-			var framework string
-			if projectFile.Framework == "" {
-				framework = pf.Frameworks
-			} else {
-				framework = pf.Framework
-			}
-
-			setRiskScore(packageInfo)
-
-			scannerResponse := &models.ScannerResponse{
-				Packages:    packageInfo,
-				Framework:   framework,
-				Name:        pf.Name,
-				ServiceName: pf.ServiceName,
-			}
+			s.finaliseScan(pf, scannerResponse, options)
 
 			scans <- scannermodels.ConcurrentScanResult{
 				Project:     scannerResponse,
-				Err:         err,
 				ServiceName: pf.ServiceName,
 				ProjectName: pf.Name,
 			}
@@ -110,12 +120,12 @@ func (s *Scanner) ScanProjects(ctx context.Context) (models.ScanAllResponse, err
 	return result, nil
 }
 
-func (s *Scanner) ScanProject(root string, ctx context.Context) ([]models.ScannerResponse, error) {
-	defer scannerCleanUp()
+func (s *Scanner) ScanProject(root string, options scannermodels.ScanOptions, ctx context.Context) ([]models.ScannerResponse, error) {
 	projectFiles, err := s.GetFilesToScan(root, ctx)
 	if err != nil {
 		return nil, err
 	}
+	setCommits(projectFiles, options)
 
 	var result []models.ScannerResponse
 	group, gCtx := errgroup.WithContext(ctx)
@@ -128,25 +138,11 @@ func (s *Scanner) ScanProject(root string, ctx context.Context) ([]models.Scanne
 				return gCtx.Err()
 
 			default:
-				//only need to check frameworks for cs projects
-				packageInfo, err := s.validateAndScan(projectFile, &mu, gCtx)
+				scannerResponse, err := s.scanProjectFile(projectFile, options, &mu, gCtx)
 				if err != nil {
 					return err
 				}
-
-				var framework string
-				if projectFile.Framework == "" {
-					framework = projectFile.Frameworks
-				} else {
-					framework = projectFile.Framework
-				}
-
-				scannerResponse := &models.ScannerResponse{
-					Packages:    packageInfo,
-					Framework:   framework,
-					Name:        projectFile.Name,
-					ServiceName: projectFile.ServiceName,
-				}
+				s.finaliseScan(projectFile, scannerResponse, options)
 
 				mu.Lock()
 				result = append(result, *scannerResponse)
@@ -204,6 +200,13 @@ func (s *Scanner) GetFilesToScan(root string, ctx context.Context) ([]scannermod
 						return nil
 					}
 
+					manifest, err := os.ReadFile(path)
+					if err != nil {
+						return fmt.Errorf("error reading manifest %s: %w", path, err)
+					}
+					project.ManifestHash = scanresultcache.HashManifest(manifest)
+					project.Repository = repositoryDirectory(root, path)
+
 					mu.Lock()
 					projects = append(projects, project)
 					mu.Unlock()
@@ -227,7 +230,146 @@ func (s *Scanner) GetFilesToScan(root string, ctx context.Context) ([]scannermod
 	return projects, nil
 }
 
-func (s *Scanner) validateAndScan(projectFile scannermodels.Project, mu *sync.Mutex, ctx context.Context) ([]models.ScannedPackage, error) {
+func (s *Scanner) getFilesFromFileSystem(fsys fs.FS) ([]scannermodels.Project, error) {
+	var projects []scannermodels.Project
+
+	walkErr := fs.WalkDir(fsys, ".", func(path string, dir fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("error walking dir: %w", err)
+		}
+
+		isCsProj := strings.HasSuffix(path, ".csproj")
+		isNpmProj := strings.HasSuffix(path, "package-lock.json")
+		if dir.IsDir() || !(isCsProj || isNpmProj) {
+			return nil
+		}
+
+		manifest, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return fmt.Errorf("error reading manifest %s: %w", path, err)
+		}
+
+		serviceName, _, _ := strings.Cut(path, "/")
+
+		var project scannermodels.Project
+		if isCsProj {
+			csProject, err := s.packageReader.ParseCsProject(manifest, serviceName, strings.TrimSuffix(filepath.Base(path), ".csproj"))
+			if err != nil {
+				return fmt.Errorf("error reading C# project %w", err)
+			}
+
+			project = scannermapper.MapCsProjToProject(&csProject)
+		} else {
+			npmProject, err := s.packageReader.ParsePackageLock(manifest)
+			if err != nil {
+				return fmt.Errorf("error reading Npm project %s %w", path, err)
+			}
+
+			if npmProject.ServiceName == "" {
+				npmProject.ServiceName = serviceName
+			}
+			project = scannermapper.MapNpmResultToProject(npmProject)
+		}
+
+		project.ManifestHash = scanresultcache.HashManifest(manifest)
+		project.Repository = serviceName
+		projects = append(projects, project)
+
+		return nil
+	})
+	if walkErr != nil {
+		return nil, walkErr
+	}
+
+	return projects, nil
+}
+
+func (s *Scanner) scanProjectFile(projectFile scannermodels.Project, options scannermodels.ScanOptions, mu *sync.Mutex, ctx context.Context) (*models.ScannerResponse, error) {
+	advisorySource, err := s.advisorySources.Get(options.Source, options.GithubApi)
+	if err != nil {
+		return nil, err
+	}
+
+	databaseTimestamp, err := advisorySource.GetAdvisoryDatabaseTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !options.UseCache || projectFile.ManifestHash == "" {
+		packageInfo, err := s.validateAndScan(projectFile, advisorySource, mu, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return mapScannerResponse(projectFile, packageInfo, options.Source, databaseTimestamp), nil
+	}
+
+	key := scanresultcache.Key(projectFile.ManifestHash, projectFile.Ecosystem, options.Source+":"+options.GithubApi)
+	cached, err := s.scanResultCache.Get(key)
+	if err != nil {
+		//a broken cache entry shouldnt fail the scan, we just rescan
+		fmt.Printf("\n%v, rescanning %s", err, projectFile.Name)
+	}
+
+	if cached != nil {
+		reusable, err := s.isCachedResultReusable(projectFile, advisorySource, cached, databaseTimestamp, mu, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if reusable {
+			fmt.Printf("\nNo changes to %s since %s, reusing previous scan", projectFile.Name, cached.ScannedAt.Format("2006-01-02"))
+			setCurrentVersions(cached.Response.Packages, projectFile.PackagesAndVersion)
+			s.cacheScanResult(key, cached.Response, databaseTimestamp)
+
+			return mapScannerResponse(projectFile, cached.Response.Packages, options.Source, databaseTimestamp), nil
+		}
+	}
+
+	packageInfo, err := s.validateAndScan(projectFile, advisorySource, mu, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	scannerResponse := mapScannerResponse(projectFile, packageInfo, options.Source, databaseTimestamp)
+	s.cacheScanResult(key, *scannerResponse, databaseTimestamp)
+
+	return scannerResponse, nil
+}
+
+// isCachedResultReusable checks the packages for advisories published or updated since the result was cached
+func (s *Scanner) isCachedResultReusable(projectFile scannermodels.Project, advisorySource advisorysourceservice.AdvisorySource, cached *scanresultcache.CachedScanResult,
+	databaseTimestamp time.Time, mu *sync.Mutex, ctx context.Context) (bool, error) {
+	if !cached.AdvisoryDatabaseTimestamp.Before(databaseTimestamp) {
+		return true, nil
+	}
+
+	if len(projectFile.PackagesAndVersion) == 0 {
+		return true, nil
+	}
+
+	updated, err := s.sendBatchedPackages(projectFile, mu, func(batch map[string]string) ([]models.ScannedPackage, error) {
+		return advisorySource.GetPackagesInfoUpdatedSince(projectFile.Ecosystem, batch, cached.AdvisoryDatabaseTimestamp, ctx)
+	})
+	if err != nil {
+		return false, fmt.Errorf("error checking for new advisories on %s: %w", projectFile.Name, err)
+	}
+
+	return len(updated) == 0, nil
+}
+
+func (s *Scanner) cacheScanResult(key string, scannerResponse models.ScannerResponse, databaseTimestamp time.Time) {
+	err := s.scanResultCache.Set(key, scanresultcache.CachedScanResult{
+		Response:                  scannerResponse,
+		AdvisoryDatabaseTimestamp: databaseTimestamp,
+		ScannedAt:                 time.Now(),
+	})
+	if err != nil {
+		fmt.Printf("\nerror caching scan result: %v", err) //log but dont fail
+	}
+}
+
+func (s *Scanner) validateAndScan(projectFile scannermodels.Project, advisorySource advisorysourceservice.AdvisorySource, mu *sync.Mutex, ctx context.Context) ([]models.ScannedPackage, error) {
 	//only need to check frameworks for cs projects
 	if (projectFile.Framework == "" && projectFile.Frameworks == "") &&
 		projectFile.Ecosystem == ecosystemconstants.Nuget {
@@ -243,13 +385,15 @@ func (s *Scanner) validateAndScan(projectFile scannermodels.Project, mu *sync.Mu
 	packagesLength := len(projectFile.PackagesAndVersion)
 
 	if packagesLength >= batchSize {
-		responses, err := s.sendBatchedPackages(projectFile, mu, ctx)
+		responses, err := s.sendBatchedPackages(projectFile, mu, func(batch map[string]string) ([]models.ScannedPackage, error) {
+			return advisorySource.GetPackagesInfo(projectFile.Ecosystem, batch, ctx)
+		})
 		if err != nil {
 			return nil, fmt.Errorf("error handling batched packages %s", err)
 		}
 		packageInfo = responses
 	} else {
-		response, err := s.client.GetPackagesInfo(projectFile.Ecosystem, projectFile.PackagesAndVersion, ctx)
+		response, err := advisorySource.GetPackagesInfo(projectFile.Ecosystem, projectFile.PackagesAndVersion, ctx)
 		if err != nil {
 			return nil, fmt.Errorf("\nerror, getting packages from client: %w", err)
 		}
@@ -259,19 +403,18 @@ func (s *Scanner) validateAndScan(projectFile scannermodels.Project, mu *sync.Mu
 	return packageInfo, nil
 }
 
-func (s *Scanner) sendBatchedPackages(projectFile scannermodels.Project, mu *sync.Mutex, ctx context.Context) ([]models.ScannedPackage, error) {
+func (s *Scanner) sendBatchedPackages(projectFile scannermodels.Project, mu *sync.Mutex,
+	getPackages func(batch map[string]string) ([]models.ScannedPackage, error)) ([]models.ScannedPackage, error) {
 	batch := make(map[string]string)
-	itemCount := 0
 
 	var result []models.ScannedPackage
 
 	for packageName, version := range projectFile.PackagesAndVersion {
 		batch[packageName] = version
-		itemCount++
 
 		if len(batch) == batchSize {
 			// Process full batch
-			response, err := s.client.GetPackagesInfo(projectFile.Ecosystem, batch, ctx)
+			response, err := getPackages(batch)
 			if err != nil {
 				return nil, fmt.Errorf("error getting packages from client: %w", err)
 			}
@@ -281,13 +424,12 @@ func (s *Scanner) sendBatchedPackages(projectFile scannermodels.Project, mu *syn
 			mu.Unlock()
 
 			batch = make(map[string]string)
-			itemCount = 0
 		}
 	}
 
 	// Handle remaining items
 	if len(batch) > 0 {
-		response, err := s.client.GetPackagesInfo(projectFile.Ecosystem, batch, ctx)
+		response, err := getPackages(batch)
 		if err != nil {
 			return nil, fmt.Errorf("error getting packages from client: %w", err)
 		}
@@ -301,27 +443,64 @@ func (s *Scanner) sendBatchedPackages(projectFile scannermodels.Project, mu *syn
 	return result, nil
 }
 
-func scannerCleanUp() error {
-	if _, err := os.Stat(scannerconstants.TempDirctory); err == nil {
-		os.RemoveAll(scannerconstants.TempDirctory)
-		return err
+func mapScannerResponse(projectFile scannermodels.Project, packageInfo []models.ScannedPackage,
+	advisorySource string, databaseTimestamp time.Time) *models.ScannerResponse {
+	//only need to check frameworks for cs projects
+	var framework string
+	if projectFile.Framework == "" {
+		framework = projectFile.Frameworks
+	} else {
+		framework = projectFile.Framework
 	}
 
-	return nil
+	return &models.ScannerResponse{
+		Packages:             packageInfo,
+		Framework:            framework,
+		Name:                 projectFile.Name,
+		ServiceName:          projectFile.ServiceName,
+		AdvisorySource:       advisorySource,
+		AdvisoryDatabaseDate: databaseTimestamp,
+		CommitSha:            projectFile.CommitSha,
+	}
+}
+
+// setCommits projects that already know their commit e.g. from a dependency graph keep it
+func setCommits(projectFiles []scannermodels.Project, options scannermodels.ScanOptions) {
+	for i := range projectFiles {
+		if sha, ok := options.Commits[projectFiles[i].Repository]; ok {
+			projectFiles[i].CommitSha = sha
+		}
+	}
+}
+
+// repositoryDirectory is the first directory under root, each repository is cloned into its own
+func repositoryDirectory(root string, path string) string {
+	relativePath, err := filepath.Rel(root, path)
+	if err != nil {
+		return ""
+	}
+
+	repository, _, _ := strings.Cut(filepath.ToSlash(relativePath), "/")
+	return repository
 }
 
-func setRiskScore(packages []models.ScannedPackage) {
+// finaliseScan runs after caching so a later --include-withdrawn run can still reuse the cached result
+// and risk scores pick up the latest epss, kev and criticality settings
+func (s *Scanner) finaliseScan(projectFile scannermodels.Project, scannerResponse *models.ScannerResponse, options scannermodels.ScanOptions) {
+	if !options.IncludeWithdrawn {
+		scannerResponse.Packages = slices.DeleteFunc(scannerResponse.Packages, func(pkg models.ScannedPackage) bool {
+			return pkg.WithdrawnAt != nil
+		})
+	}
+
+	s.riskScorer.Score(projectFile, scannerResponse.Packages)
+}
 
+// setCurrentVersions restores the installed versions, these arent serialised with the cached packages
+func setCurrentVersions(packages []models.ScannedPackage, packageAndVersions map[string]string) {
 	for i := range packages {
-		switch packages[i].Severity {
-		case "critical":
-			packages[i].RiskScore = riskscoreconstants.Critical
-		case "high":
-			packages[i].RiskScore = riskscoreconstants.High
-		case "medium":
-			packages[i].RiskScore = riskscoreconstants.Medium
-		case "low":
-			packages[i].RiskScore = riskscoreconstants.Low
+		for j := range packages[i].Vulnerabilities {
+			packages[i].Vulnerabilities[j].CurrentVersion = packageAndVersions[packages[i].Vulnerabilities[j].Package.Name]
 		}
 	}
 }
@@ -0,0 +1,234 @@
+package advisorydatabaseservice
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	advisorydatabase "github.com/RobsonDevCode/deepscan/internal/advisoryDatabase"
+	osvmodels "github.com/RobsonDevCode/deepscan/internal/clients/models/osv"
+	ecosystemconstants "github.com/RobsonDevCode/deepscan/internal/scanner/constants/ecosystem"
+	advisorysourceservice "github.com/RobsonDevCode/deepscan/internal/services/advisorySourceService"
+)
+
+const exportFolder = "./export"
+
+type AdvisoryDatabaseService interface {
+	Import(path string, ctx context.Context) (*advisorydatabase.AdvisoryDatabase, error)
+	Export(outPath string) (string, error)
+}
+
+type AdvisoryDatabaseManager struct {
+	store advisorydatabase.AdvisoryDatabaseStoreService
+}
+
+func NewAdvisoryDatabaseManager(store advisorydatabase.AdvisoryDatabaseStoreService) *AdvisoryDatabaseManager {
+	return &AdvisoryDatabaseManager{
+		store: store,
+	}
+}
+
+// Import accepts an osv zip dump, a clone of the github advisory-database repo or a bundle from 'db export'
+func (m *AdvisoryDatabaseManager) Import(path string, ctx context.Context) (*advisorydatabase.AdvisoryDatabase, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading import path %s: %w", path, err)
+	}
+
+	if !info.IsDir() && strings.HasSuffix(path, ".gz") {
+		return m.importBundle(path)
+	}
+
+	// anything but a missing database is returned, starting again would throw away every advisory already imported
+	database, err := m.store.Load()
+	if errors.Is(err, os.ErrNotExist) {
+		database = advisorydatabase.NewAdvisoryDatabase() //first import
+	} else if err != nil {
+		return nil, err
+	}
+
+	imported := 0
+	malformed := 0
+	add := func(name string, reader io.Reader) error {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("import has been cancelled, %w", ctx.Err())
+		default:
+		}
+
+		// one bad record in a dump of thousands shouldnt throw the rest away, its skipped like an unsupported ecosystem
+		vulnerability, ok, err := readVulnerability(reader)
+		if err != nil {
+			malformed++
+			return nil
+		}
+
+		if ok {
+			database.Add(vulnerability)
+			imported++
+		}
+		return nil
+	}
+
+	if info.IsDir() {
+		err = importDirectory(path, add)
+	} else {
+		err = importZip(path, add)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	database.BuildDate = time.Now().UTC()
+	if err := m.store.Save(database); err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("\nImported %d advisories into %s", imported, m.store.Path())
+	if malformed > 0 {
+		fmt.Printf("\nSkipped %d malformed advisories", malformed)
+	}
+	return database, nil
+}
+
+func (m *AdvisoryDatabaseManager) Export(outPath string) (string, error) {
+	database, err := m.store.Load()
+	if err != nil {
+		return "", err
+	}
+
+	if outPath == "" {
+		if err := os.MkdirAll(exportFolder, 0755); err != nil {
+			return "", fmt.Errorf("error creating directory %s, %w", exportFolder, err)
+		}
+		outPath = filepath.Join(exportFolder, fmt.Sprintf("advisory_db_%s.json.gz", database.BuildDate.Format("2006-01-02T15-04-05")))
+	}
+
+	file, err := os.Create(outPath)
+	if err != nil {
+		return "", fmt.Errorf("error creating bundle %s: %w", outPath, err)
+	}
+	defer file.Close()
+
+	if err := advisorydatabase.Write(database, file); err != nil {
+		return "", err
+	}
+
+	return outPath, nil
+}
+
+func (m *AdvisoryDatabaseManager) importBundle(path string) (*advisorydatabase.AdvisoryDatabase, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening bundle %s: %w", path, err)
+	}
+	defer file.Close()
+
+	// keep the build date from the bundle so reports show when the data was actually built
+	database, err := advisorydatabase.Read(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.store.Save(database); err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("\nImported bundle with %d advisories into %s", len(database.Vulnerabilities), m.store.Path())
+	return database, nil
+}
+
+func importZip(path string, add func(name string, reader io.Reader) error) error {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return fmt.Errorf("error opening osv zip %s: %w", path, err)
+	}
+	defer archive.Close()
+
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() || !strings.HasSuffix(file.Name, ".json") {
+			continue
+		}
+
+		reader, err := file.Open()
+		if err != nil {
+			return fmt.Errorf("error opening %s in zip: %w", file.Name, err)
+		}
+
+		err = add(file.Name, reader)
+		reader.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func importDirectory(root string, add func(name string, reader io.Reader) error) error {
+	return filepath.WalkDir(root, func(path string, dir fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("error walking dir: %w", err)
+		}
+
+		if dir.IsDir() {
+			if dir.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !strings.HasSuffix(path, ".json") {
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("error opening %s: %w", path, err)
+		}
+		defer file.Close()
+
+		return add(path, file)
+	})
+}
+
+// readVulnerability only keeps records for ecosystems we can scan, anything else is skipped
+func readVulnerability(reader io.Reader) (osvmodels.Vulnerability, bool, error) {
+	var vulnerability osvmodels.Vulnerability
+	if err := json.NewDecoder(reader).Decode(&vulnerability); err != nil {
+		return osvmodels.Vulnerability{}, false, err
+	}
+
+	if vulnerability.Id == "" {
+		return osvmodels.Vulnerability{}, false, nil
+	}
+
+	supported := []string{
+		advisorysourceservice.ToOsvEcosystem(ecosystemconstants.Npm),
+		advisorysourceservice.ToOsvEcosystem(ecosystemconstants.Nuget),
+	}
+
+	var affected []osvmodels.Affected
+	for _, a := range vulnerability.Affected {
+		for _, ecosystem := range supported {
+			if strings.EqualFold(a.Package.Ecosystem, ecosystem) {
+				affected = append(affected, a)
+			}
+		}
+	}
+
+	if len(affected) == 0 {
+		return osvmodels.Vulnerability{}, false, nil
+	}
+
+	vulnerability.Affected = affected
+	return vulnerability, true, nil
+}
@@ -0,0 +1,59 @@
+package advisorydatabaseservice
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	advisorydatabase "github.com/RobsonDevCode/deepscan/internal/advisoryDatabase"
+)
+
+// memoryStore keeps the database in memory so imports dont touch the users real advisory database
+type memoryStore struct {
+	database *advisorydatabase.AdvisoryDatabase
+}
+
+func (s *memoryStore) Load() (*advisorydatabase.AdvisoryDatabase, error) {
+	if s.database == nil {
+		return nil, os.ErrNotExist
+	}
+
+	return s.database, nil
+}
+
+func (s *memoryStore) Save(database *advisorydatabase.AdvisoryDatabase) error {
+	s.database = database
+	return nil
+}
+
+func (s *memoryStore) Path() string {
+	return "memory"
+}
+
+func TestImportSkipsMalformedAdvisories(t *testing.T) {
+	root := t.TempDir()
+	advisories := map[string]string{
+		"GHSA-aaaa-bbbb-cccc.json": `{"id":"GHSA-aaaa-bbbb-cccc","affected":[{"package":{"name":"lodash","ecosystem":"npm"}}]}`,
+		"PYSEC-2024-1.json":        `{"id":"PYSEC-2024-1","affected":[{"package":{"name":"requests","ecosystem":"PyPI"}}]}`,
+		"truncated.json":           `{"id":"GHSA-dddd-eeee-ffff","affected":[`,
+		"not-json.json":            `<html>rate limited</html>`,
+	}
+	for name, content := range advisories {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0600); err != nil {
+			t.Fatalf("error writing advisory: %v", err)
+		}
+	}
+
+	database, err := NewAdvisoryDatabaseManager(&memoryStore{}).Import(root, context.Background())
+	if err != nil {
+		t.Fatalf("expected malformed advisories to be skipped, got %v", err)
+	}
+
+	if len(database.Vulnerabilities) != 1 {
+		t.Fatalf("expected only the npm advisory to be imported, got %d", len(database.Vulnerabilities))
+	}
+	if _, ok := database.Vulnerabilities["GHSA-aaaa-bbbb-cccc"]; !ok {
+		t.Errorf("expected GHSA-aaaa-bbbb-cccc to be imported, got %v", database.Vulnerabilities)
+	}
+}
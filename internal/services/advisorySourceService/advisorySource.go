@@ -0,0 +1,74 @@
+package advisorysourceservice
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	advisorydatabase "github.com/RobsonDevCode/deepscan/internal/advisoryDatabase"
+	"github.com/RobsonDevCode/deepscan/internal/clients"
+	"github.com/RobsonDevCode/deepscan/internal/clients/models"
+	osvclient "github.com/RobsonDevCode/deepscan/internal/clients/osvClient"
+	advisorysources "github.com/RobsonDevCode/deepscan/internal/constants/advisorySources"
+)
+
+type AdvisorySource interface {
+	GetPackagesInfo(ecosystem string, packageAndVersions map[string]string, ctx context.Context) ([]models.ScannedPackage, error)
+	GetPackagesInfoUpdatedSince(ecosystem string, packageAndVersions map[string]string, since time.Time, ctx context.Context) ([]models.ScannedPackage, error)
+	GetAdvisoryDatabaseTimestamp(ctx context.Context) (time.Time, error)
+}
+
+type AdvisorySourceSelector interface {
+	Get(source string, githubApi string) (AdvisorySource, error)
+}
+
+type AdvisorySources struct {
+	github        AdvisorySource
+	githubGraphql AdvisorySource
+	osv           AdvisorySource
+	offline       AdvisorySource
+}
+
+func NewAdvisorySources(githubClient clients.GithubClientService, githubGraphqlClient clients.GithubGraphqlClientService,
+	osvClient osvclient.OsvClientService, advisoryDatabaseStore advisorydatabase.AdvisoryDatabaseStoreService) *AdvisorySources {
+	return &AdvisorySources{
+		github:        NewGithubAdvisorySource(githubClient),
+		githubGraphql: NewGithubGraphqlAdvisorySource(githubGraphqlClient),
+		osv:           NewOsvAdvisorySource(osvClient),
+		offline:       NewOfflineAdvisorySource(advisoryDatabaseStore),
+	}
+}
+
+func (a *AdvisorySources) Get(source string, githubApi string) (AdvisorySource, error) {
+	github, err := a.getGithub(githubApi)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(source) {
+	case "", advisorysources.Github:
+		return github, nil
+	case advisorysources.Osv:
+		return a.osv, nil
+	case advisorysources.Both:
+		return NewMergedAdvisorySource(github, a.osv), nil
+	case advisorysources.Offline:
+		return a.offline, nil
+	default:
+		return nil, fmt.Errorf("advisory source %s not supported, expected one of %s", source,
+			strings.Join(advisorysources.AdvisorySourceOptions, ", "))
+	}
+}
+
+func (a *AdvisorySources) getGithub(githubApi string) (AdvisorySource, error) {
+	switch strings.ToLower(githubApi) {
+	case "", advisorysources.Rest:
+		return a.github, nil
+	case advisorysources.Graphql:
+		return a.githubGraphql, nil
+	default:
+		return nil, fmt.Errorf("github api %s not supported, expected one of %s", githubApi,
+			strings.Join(advisorysources.GithubApiOptions, ", "))
+	}
+}
@@ -0,0 +1,51 @@
+package advisorysourceservice
+
+import (
+	"context"
+	"time"
+
+	"github.com/RobsonDevCode/deepscan/internal/clients"
+	"github.com/RobsonDevCode/deepscan/internal/clients/models"
+	advisorysources "github.com/RobsonDevCode/deepscan/internal/constants/advisorySources"
+)
+
+type GithubAdvisorySource struct {
+	githubClient clients.GithubClientService
+}
+
+func NewGithubAdvisorySource(githubClient clients.GithubClientService) *GithubAdvisorySource {
+	return &GithubAdvisorySource{
+		githubClient: githubClient,
+	}
+}
+
+func (g *GithubAdvisorySource) GetPackagesInfo(ecosystem string, packageAndVersions map[string]string, ctx context.Context) ([]models.ScannedPackage, error) {
+	packages, err := g.githubClient.GetPackagesInfo(ecosystem, packageAndVersions, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return normaliseGithubPackages(packages), nil
+}
+
+func (g *GithubAdvisorySource) GetPackagesInfoUpdatedSince(ecosystem string, packageAndVersions map[string]string, since time.Time, ctx context.Context) ([]models.ScannedPackage, error) {
+	packages, err := g.githubClient.GetPackagesInfoUpdatedSince(ecosystem, packageAndVersions, since, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return normaliseGithubPackages(packages), nil
+}
+
+func (g *GithubAdvisorySource) GetAdvisoryDatabaseTimestamp(ctx context.Context) (time.Time, error) {
+	return g.githubClient.GetAdvisoryDatabaseTimestamp(ctx)
+}
+
+func normaliseGithubPackages(packages []models.ScannedPackage) []models.ScannedPackage {
+	for i := range packages {
+		packages[i].Source = advisorysources.Github
+		packages[i].Aliases = appendAliases(packages[i].Aliases, packages[i].GhsaId, packages[i].CveId)
+	}
+
+	return packages
+}
@@ -0,0 +1,128 @@
+package advisorysourceservice
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/RobsonDevCode/deepscan/internal/clients"
+	"github.com/RobsonDevCode/deepscan/internal/clients/models"
+	graphqlmodels "github.com/RobsonDevCode/deepscan/internal/clients/models/githubGraphql"
+	advisorysources "github.com/RobsonDevCode/deepscan/internal/constants/advisorySources"
+	advisorytypes "github.com/RobsonDevCode/deepscan/internal/constants/advisoryTypes"
+	"github.com/RobsonDevCode/deepscan/internal/versioning"
+)
+
+// GithubGraphqlAdvisorySource avoids the rest query string limits on large lockfiles,
+// graphql cant filter by version so we match the ranges ourselves
+type GithubGraphqlAdvisorySource struct {
+	graphqlClient clients.GithubGraphqlClientService
+}
+
+func NewGithubGraphqlAdvisorySource(graphqlClient clients.GithubGraphqlClientService) *GithubGraphqlAdvisorySource {
+	return &GithubGraphqlAdvisorySource{
+		graphqlClient: graphqlClient,
+	}
+}
+
+func (g *GithubGraphqlAdvisorySource) GetPackagesInfo(ecosystem string, packageAndVersions map[string]string, ctx context.Context) ([]models.ScannedPackage, error) {
+	return g.query(ecosystem, packageAndVersions, time.Time{}, ctx)
+}
+
+func (g *GithubGraphqlAdvisorySource) GetPackagesInfoUpdatedSince(ecosystem string, packageAndVersions map[string]string, since time.Time, ctx context.Context) ([]models.ScannedPackage, error) {
+	return g.query(ecosystem, packageAndVersions, since, ctx)
+}
+
+func (g *GithubGraphqlAdvisorySource) GetAdvisoryDatabaseTimestamp(ctx context.Context) (time.Time, error) {
+	return g.graphqlClient.GetAdvisoryDatabaseTimestamp(ctx)
+}
+
+func (g *GithubGraphqlAdvisorySource) query(ecosystem string, packageAndVersions map[string]string, updatedSince time.Time, ctx context.Context) ([]models.ScannedPackage, error) {
+	if len(packageAndVersions) == 0 {
+		return nil, nil
+	}
+
+	var packageNames []string
+	for packageName := range packageAndVersions {
+		packageNames = append(packageNames, packageName)
+	}
+
+	vulnerabilities, err := g.graphqlClient.GetSecurityVulnerabilities(ecosystem, packageNames, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []models.ScannedPackage
+	indexByAdvisory := make(map[string]int)
+
+	for _, vulnerability := range vulnerabilities {
+		if !updatedSince.IsZero() && vulnerability.Advisory.UpdatedAt.Before(updatedSince) {
+			continue
+		}
+
+		packageName, version, ok := lookupPackage(packageAndVersions, vulnerability.Package.Name, ecosystem)
+		if !ok || !versioning.InGithubRange(version, vulnerability.VulnerableVersionRange) {
+			continue
+		}
+
+		index, exists := indexByAdvisory[vulnerability.Advisory.GhsaId]
+		if !exists {
+			result = append(result, mapGraphqlAdvisory(vulnerability.Advisory))
+			index = len(result) - 1
+			indexByAdvisory[vulnerability.Advisory.GhsaId] = index
+		}
+
+		var firstPatchedVersion string
+		if vulnerability.FirstPatchedVersion != nil {
+			firstPatchedVersion = vulnerability.FirstPatchedVersion.Identifier
+		}
+
+		result[index].Vulnerabilities = append(result[index].Vulnerabilities, models.Vulnerability{
+			CurrentVersion:         version,
+			Package:                models.Package{Ecosystem: ecosystem, Name: packageName},
+			VulnerableVersionRange: vulnerability.VulnerableVersionRange,
+			FirstPatchedVersion:    firstPatchedVersion,
+		})
+	}
+
+	return result, nil
+}
+
+func mapGraphqlAdvisory(advisory graphqlmodels.SecurityAdvisory) models.ScannedPackage {
+	scannedPackage := models.ScannedPackage{
+		GhsaId:      advisory.GhsaId,
+		Type:        advisorytypes.Reviewed,
+		Source:      advisorysources.Github,
+		Summary:     advisory.Summary,
+		Description: advisory.Description,
+		Severity:    mapOsvSeverity(advisory.Severity), // graphql uses the same upper case names as osv
+		HtmlUrl:     advisory.Permalink,
+		UpdatedAt:   advisory.UpdatedAt,
+		PublishedAt: advisory.PublishedAt,
+		WithdrawnAt: advisory.WithdrawnAt,
+	}
+
+	if strings.EqualFold(advisory.Classification, advisorytypes.Malware) {
+		scannedPackage.Type = advisorytypes.Malware
+	}
+
+	for _, identifier := range advisory.Identifiers {
+		scannedPackage.Identifiers = append(scannedPackage.Identifiers, models.Identifier{Type: identifier.Type, Value: identifier.Value})
+
+		if scannedPackage.CveId == "" && strings.EqualFold(identifier.Type, "CVE") {
+			scannedPackage.CveId = identifier.Value
+		}
+	}
+
+	// graphql reports 0 when an advisory hasnt been scored
+	if advisory.Cvss != nil && advisory.Cvss.VectorString != "" {
+		scannedPackage.Cvss = &models.Cvss{Score: advisory.Cvss.Score, VectorString: advisory.Cvss.VectorString}
+	}
+
+	for _, cwe := range advisory.Cwes.Nodes {
+		scannedPackage.Cwes = append(scannedPackage.Cwes, models.Cwe{CweId: cwe.CweId, Name: cwe.Name})
+	}
+
+	scannedPackage.Aliases = appendAliases(nil, scannedPackage.GhsaId, scannedPackage.CveId)
+	return scannedPackage
+}
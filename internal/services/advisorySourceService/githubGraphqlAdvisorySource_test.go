@@ -0,0 +1,170 @@
+package advisorysourceservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	cache "github.com/RobsonDevCode/deepscan/internal/caching"
+	"github.com/RobsonDevCode/deepscan/internal/clients"
+	graphqlmodels "github.com/RobsonDevCode/deepscan/internal/clients/models/githubGraphql"
+	"github.com/RobsonDevCode/deepscan/internal/configuration"
+	ecosystemconstants "github.com/RobsonDevCode/deepscan/internal/scanner/constants/ecosystem"
+)
+
+const cursorPrefix = "cursor-"
+
+// graphqlStub answers the aliased securityVulnerabilities query, pages holds the ghsa ids on each page per package
+// and a page is picked by the cursor the request sends back
+func graphqlStub(t *testing.T, pages map[string][][]string, cursorsSeen *[]string) *httptest.Server {
+	var mu sync.Mutex
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/graphql" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+
+		var request graphqlmodels.GraphqlRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("error decoding graphql request: %v", err)
+			return
+		}
+
+		data := make(map[string]any)
+		for variable, value := range request.Variables {
+			alias, ok := strings.CutPrefix(variable, "p")
+			if !ok {
+				continue
+			}
+
+			page := 0
+			if cursor, ok := request.Variables["c"+alias].(string); ok {
+				mu.Lock()
+				*cursorsSeen = append(*cursorsSeen, cursor)
+				mu.Unlock()
+
+				page, _ = strconv.Atoi(strings.TrimPrefix(cursor, cursorPrefix))
+				if !strings.Contains(request.Query, "after: $c"+alias) {
+					t.Errorf("expected the cursor for %s to be passed as after", value)
+				}
+			}
+
+			packagePages := pages[value.(string)]
+			var nodes []map[string]any
+			if page < len(packagePages) {
+				for _, ghsaId := range packagePages[page] {
+					nodes = append(nodes, map[string]any{
+						"advisory":               map[string]any{"ghsaId": ghsaId, "severity": "HIGH", "identifiers": []any{}},
+						"package":                map[string]any{"name": value, "ecosystem": "NPM"},
+						"vulnerableVersionRange": "< 99.0.0",
+					})
+				}
+			}
+
+			data["p"+alias] = map[string]any{
+				"nodes":    nodes,
+				"pageInfo": map[string]any{"hasNextPage": page+1 < len(packagePages), "endCursor": fmt.Sprintf("%s%d", cursorPrefix, page+1)},
+			}
+		}
+
+		json.NewEncoder(w).Encode(map[string]any{"data": data})
+	}))
+}
+
+func TestGraphqlAdvisorySourceFollowsEndCursor(t *testing.T) {
+	tests := []struct {
+		name            string
+		pages           map[string][][]string
+		expectedGhsaIds []string
+		expectedCursors []string
+	}{
+		{
+			name:            "single page",
+			pages:           map[string][][]string{"lodash": {{"GHSA-0001"}}},
+			expectedGhsaIds: []string{"GHSA-0001"},
+		},
+		{
+			name:            "three pages",
+			pages:           map[string][][]string{"lodash": {{"GHSA-0001", "GHSA-0002"}, {"GHSA-0003"}, {"GHSA-0004"}}},
+			expectedGhsaIds: []string{"GHSA-0001", "GHSA-0002", "GHSA-0003", "GHSA-0004"},
+			expectedCursors: []string{"cursor-1", "cursor-2"},
+		},
+		{
+			name: "only the package with more pages is queried again",
+			pages: map[string][][]string{
+				"lodash": {{"GHSA-0001"}, {"GHSA-0002"}},
+				"react":  {{"GHSA-0003"}},
+			},
+			expectedGhsaIds: []string{"GHSA-0001", "GHSA-0002", "GHSA-0003"},
+			expectedCursors: []string{"cursor-1"},
+		},
+		{
+			name:            "same advisory on two pages is one finding",
+			pages:           map[string][][]string{"lodash": {{"GHSA-0001"}, {"GHSA-0001"}}},
+			expectedGhsaIds: []string{"GHSA-0001"},
+			expectedCursors: []string{"cursor-1"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var cursorsSeen []string
+			server := graphqlStub(t, test.pages, &cursorsSeen)
+			defer server.Close()
+
+			config := &configuration.Config{}
+			config.GithubClientSettings.BaseUrl = server.URL + "/"
+			graphqlClient, err := clients.NewGithubGraphqlClient(config, &cache.Cache{}, clients.PersonalAccessToken("test-token"))
+			if err != nil {
+				t.Fatalf("unexpected error creating client: %v", err)
+			}
+
+			packageAndVersions := make(map[string]string)
+			for packageName := range test.pages {
+				packageAndVersions[packageName] = "1.0.0"
+			}
+
+			findings, err := NewGithubGraphqlAdvisorySource(graphqlClient).GetPackagesInfo(ecosystemconstants.Npm, packageAndVersions, context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var ghsaIds []string
+			for _, finding := range findings {
+				ghsaIds = append(ghsaIds, finding.GhsaId)
+			}
+			slices.Sort(ghsaIds)
+			if !slices.Equal(ghsaIds, test.expectedGhsaIds) {
+				t.Errorf("expected %v, got %v", test.expectedGhsaIds, ghsaIds)
+			}
+			if !slices.Equal(cursorsSeen, test.expectedCursors) {
+				t.Errorf("expected cursors %v to be followed, got %v", test.expectedCursors, cursorsSeen)
+			}
+		})
+	}
+}
+
+func TestGraphqlAdvisorySourceReportsErrorsWithListIndexes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"errors":[{"type":"SERVICE_UNAVAILABLE","message":"advisory lookup timed out","path":["p0","nodes",3,"advisory"]}]}`)
+	}))
+	defer server.Close()
+
+	config := &configuration.Config{}
+	config.GithubClientSettings.BaseUrl = server.URL + "/"
+	graphqlClient, err := clients.NewGithubGraphqlClient(config, &cache.Cache{}, clients.PersonalAccessToken("test-token"))
+	if err != nil {
+		t.Fatalf("unexpected error creating client: %v", err)
+	}
+
+	_, err = NewGithubGraphqlAdvisorySource(graphqlClient).GetPackagesInfo(ecosystemconstants.Npm, map[string]string{"lodash": "1.0.0"}, context.Background())
+	if err == nil || !strings.Contains(err.Error(), "advisory lookup timed out") {
+		t.Errorf("expected the graphql error message, got %v", err)
+	}
+}
@@ -0,0 +1,194 @@
+package advisorysourceservice
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/RobsonDevCode/deepscan/internal/clients/models"
+	advisorytypes "github.com/RobsonDevCode/deepscan/internal/constants/advisoryTypes"
+	"golang.org/x/sync/errgroup"
+)
+
+// MergedAdvisorySource queries several sources and collapses findings that share a GHSA or CVE id
+type MergedAdvisorySource struct {
+	sources []AdvisorySource
+}
+
+func NewMergedAdvisorySource(sources ...AdvisorySource) *MergedAdvisorySource {
+	return &MergedAdvisorySource{
+		sources: sources,
+	}
+}
+
+func (m *MergedAdvisorySource) GetPackagesInfo(ecosystem string, packageAndVersions map[string]string, ctx context.Context) ([]models.ScannedPackage, error) {
+	return m.queryAll(func(source AdvisorySource, gCtx context.Context) ([]models.ScannedPackage, error) {
+		return source.GetPackagesInfo(ecosystem, packageAndVersions, gCtx)
+	}, ctx)
+}
+
+func (m *MergedAdvisorySource) GetPackagesInfoUpdatedSince(ecosystem string, packageAndVersions map[string]string, since time.Time, ctx context.Context) ([]models.ScannedPackage, error) {
+	return m.queryAll(func(source AdvisorySource, gCtx context.Context) ([]models.ScannedPackage, error) {
+		return source.GetPackagesInfoUpdatedSince(ecosystem, packageAndVersions, since, gCtx)
+	}, ctx)
+}
+
+func (m *MergedAdvisorySource) GetAdvisoryDatabaseTimestamp(ctx context.Context) (time.Time, error) {
+	var latest time.Time
+	for _, source := range m.sources {
+		timestamp, err := source.GetAdvisoryDatabaseTimestamp(ctx)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		if timestamp.After(latest) {
+			latest = timestamp
+		}
+	}
+
+	return latest, nil
+}
+
+func (m *MergedAdvisorySource) queryAll(query func(source AdvisorySource, gCtx context.Context) ([]models.ScannedPackage, error),
+	ctx context.Context) ([]models.ScannedPackage, error) {
+	results := make([][]models.ScannedPackage, len(m.sources))
+	group, gCtx := errgroup.WithContext(ctx)
+
+	for i, source := range m.sources {
+		group.Go(func() error {
+			packages, err := query(source, gCtx)
+			if err != nil {
+				return err
+			}
+
+			results[i] = packages
+			return nil
+		})
+	}
+
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	return MergeFindings(results...), nil
+}
+
+// MergeFindings earlier sources win when both have a value, later sources only fill in the gaps
+func MergeFindings(findings ...[]models.ScannedPackage) []models.ScannedPackage {
+	var merged []models.ScannedPackage
+	indexByAlias := make(map[string]int)
+
+	for _, sourceFindings := range findings {
+		for _, finding := range sourceFindings {
+			existing := -1
+			for _, alias := range advisoryIds(finding) {
+				if index, ok := indexByAlias[strings.ToUpper(alias)]; ok {
+					existing = index
+					break
+				}
+			}
+
+			if existing == -1 {
+				merged = append(merged, finding)
+				existing = len(merged) - 1
+			} else {
+				merged[existing] = mergeFinding(merged[existing], finding)
+			}
+
+			for _, alias := range advisoryIds(merged[existing]) {
+				indexByAlias[strings.ToUpper(alias)] = existing
+			}
+		}
+	}
+
+	return merged
+}
+
+func mergeFinding(existing models.ScannedPackage, finding models.ScannedPackage) models.ScannedPackage {
+	existing.Aliases = appendAliases(existing.Aliases, finding.Aliases...)
+
+	if !slices.Contains(strings.Split(existing.Source, ","), finding.Source) {
+		existing.Source = existing.Source + "," + finding.Source
+	}
+
+	if existing.GhsaId == "" {
+		existing.GhsaId = finding.GhsaId
+	}
+	if existing.CveId == "" {
+		existing.CveId = finding.CveId
+	}
+	// a malware flag from any source wins, we would rather over report a malicious package
+	if existing.Type == "" || finding.Type == advisorytypes.Malware {
+		existing.Type = finding.Type
+	}
+	if existing.Summary == "" {
+		existing.Summary = finding.Summary
+	}
+	if existing.Description == "" {
+		existing.Description = finding.Description
+	}
+	if existing.Severity == "" || existing.Severity == "unknown" {
+		existing.Severity = finding.Severity
+	}
+	if existing.HtmlUrl == "" {
+		existing.HtmlUrl = finding.HtmlUrl
+	}
+	if existing.Cvss == nil {
+		existing.Cvss = finding.Cvss
+	}
+	if len(existing.Cwes) == 0 {
+		existing.Cwes = finding.Cwes
+	}
+	if existing.PublishedAt.IsZero() {
+		existing.PublishedAt = finding.PublishedAt
+	}
+	if existing.WithdrawnAt == nil {
+		existing.WithdrawnAt = finding.WithdrawnAt
+	}
+
+	for _, identifier := range finding.Identifiers {
+		found := slices.ContainsFunc(existing.Identifiers, func(i models.Identifier) bool {
+			return strings.EqualFold(i.Value, identifier.Value)
+		})
+
+		if !found {
+			existing.Identifiers = append(existing.Identifiers, identifier)
+		}
+	}
+
+	for _, vulnerability := range finding.Vulnerabilities {
+		found := slices.ContainsFunc(existing.Vulnerabilities, func(v models.Vulnerability) bool {
+			return v.Package.Name == vulnerability.Package.Name
+		})
+
+		if !found {
+			existing.Vulnerabilities = append(existing.Vulnerabilities, vulnerability)
+		}
+	}
+
+	return existing
+}
+
+func advisoryIds(finding models.ScannedPackage) []string {
+	return appendAliases(nil, append([]string{finding.GhsaId, finding.CveId}, finding.Aliases...)...)
+}
+
+// appendAliases skips blanks and duplicates, ids are compared case insensitively as sources disagree on GHSA casing
+func appendAliases(aliases []string, ids ...string) []string {
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+
+		duplicate := slices.ContainsFunc(aliases, func(alias string) bool {
+			return strings.EqualFold(alias, id)
+		})
+		if !duplicate {
+			aliases = append(aliases, id)
+		}
+	}
+
+	return aliases
+}
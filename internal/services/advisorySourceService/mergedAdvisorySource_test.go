@@ -0,0 +1,142 @@
+package advisorysourceservice
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/RobsonDevCode/deepscan/internal/clients/models"
+	advisorysources "github.com/RobsonDevCode/deepscan/internal/constants/advisorySources"
+	advisorytypes "github.com/RobsonDevCode/deepscan/internal/constants/advisoryTypes"
+)
+
+func vulnerableIn(packageNames ...string) []models.Vulnerability {
+	var vulnerabilities []models.Vulnerability
+	for _, packageName := range packageNames {
+		vulnerabilities = append(vulnerabilities, models.Vulnerability{Package: models.Package{Name: packageName}})
+	}
+
+	return vulnerabilities
+}
+
+func TestMergeFindings(t *testing.T) {
+	type expectedFinding struct {
+		ghsaId          string
+		cveId           string
+		source          string
+		summary         string
+		advisoryType    string
+		aliases         []string
+		hasCvss         bool
+		vulnerabilities []string
+	}
+
+	tests := []struct {
+		name     string
+		findings [][]models.ScannedPackage
+		expected []expectedFinding
+	}{
+		{
+			name: "same ghsa from github and osv",
+			findings: [][]models.ScannedPackage{
+				{{GhsaId: "GHSA-aaaa-bbbb-cccc", Source: advisorysources.Github, Summary: "github summary", Type: advisorytypes.Reviewed, Vulnerabilities: vulnerableIn("lodash")}},
+				{{GhsaId: "GHSA-aaaa-bbbb-cccc", CveId: "CVE-2024-0001", Source: advisorysources.Osv, Summary: "osv summary", Cvss: &models.Cvss{Score: 7.5}, Vulnerabilities: vulnerableIn("lodash")}},
+			},
+			expected: []expectedFinding{
+				{ghsaId: "GHSA-aaaa-bbbb-cccc", cveId: "CVE-2024-0001", source: "github,osv", summary: "github summary", advisoryType: advisorytypes.Reviewed,
+					hasCvss: true, vulnerabilities: []string{"lodash"}},
+			},
+		},
+		{
+			name: "ghsa casing differs between sources",
+			findings: [][]models.ScannedPackage{
+				{{GhsaId: "GHSA-aaaa-bbbb-cccc", Source: advisorysources.Github}},
+				{{GhsaId: "ghsa-aaaa-bbbb-cccc", Source: advisorysources.Osv}},
+			},
+			expected: []expectedFinding{
+				{ghsaId: "GHSA-aaaa-bbbb-cccc", source: "github,osv"},
+			},
+		},
+		{
+			name: "osv record found through its cve alias",
+			findings: [][]models.ScannedPackage{
+				{{GhsaId: "GHSA-aaaa-bbbb-cccc", CveId: "CVE-2024-0001", Source: advisorysources.Github, Vulnerabilities: vulnerableIn("lodash")}},
+				{{Aliases: []string{"PYSEC-2024-1", "CVE-2024-0001"}, Source: advisorysources.Osv, Vulnerabilities: vulnerableIn("lodash-es")}},
+			},
+			expected: []expectedFinding{
+				{ghsaId: "GHSA-aaaa-bbbb-cccc", cveId: "CVE-2024-0001", source: "github,osv",
+					aliases: []string{"PYSEC-2024-1", "CVE-2024-0001"}, vulnerabilities: []string{"lodash", "lodash-es"}},
+			},
+		},
+		{
+			name: "alias learnt from one source links a later one",
+			findings: [][]models.ScannedPackage{
+				{{GhsaId: "GHSA-aaaa-bbbb-cccc", Source: advisorysources.Github}},
+				{{GhsaId: "GHSA-aaaa-bbbb-cccc", CveId: "CVE-2024-0001", Source: advisorysources.Osv}},
+				{{CveId: "CVE-2024-0001", Source: advisorysources.Offline}},
+			},
+			expected: []expectedFinding{
+				{ghsaId: "GHSA-aaaa-bbbb-cccc", cveId: "CVE-2024-0001", source: "github,osv,offline"},
+			},
+		},
+		{
+			name: "malware from a later source wins",
+			findings: [][]models.ScannedPackage{
+				{{GhsaId: "GHSA-aaaa-bbbb-cccc", Source: advisorysources.Github, Type: advisorytypes.Reviewed}},
+				{{GhsaId: "GHSA-aaaa-bbbb-cccc", Source: advisorysources.Osv, Type: advisorytypes.Malware}},
+			},
+			expected: []expectedFinding{
+				{ghsaId: "GHSA-aaaa-bbbb-cccc", source: "github,osv", advisoryType: advisorytypes.Malware},
+			},
+		},
+		{
+			name: "different advisories stay apart",
+			findings: [][]models.ScannedPackage{
+				{{GhsaId: "GHSA-aaaa-bbbb-cccc", CveId: "CVE-2024-0001", Source: advisorysources.Github}},
+				{{GhsaId: "GHSA-dddd-eeee-ffff", CveId: "CVE-2024-0002", Source: advisorysources.Osv}},
+			},
+			expected: []expectedFinding{
+				{ghsaId: "GHSA-aaaa-bbbb-cccc", cveId: "CVE-2024-0001", source: advisorysources.Github},
+				{ghsaId: "GHSA-dddd-eeee-ffff", cveId: "CVE-2024-0002", source: advisorysources.Osv},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			merged := MergeFindings(test.findings...)
+			if len(merged) != len(test.expected) {
+				t.Fatalf("expected %d findings, got %d: %+v", len(test.expected), len(merged), merged)
+			}
+
+			for i, expected := range test.expected {
+				finding := merged[i]
+				if finding.GhsaId != expected.ghsaId || finding.CveId != expected.cveId {
+					t.Errorf("expected %s %s, got %s %s", expected.ghsaId, expected.cveId, finding.GhsaId, finding.CveId)
+				}
+				if finding.Source != expected.source {
+					t.Errorf("expected source %s, got %s", expected.source, finding.Source)
+				}
+				if expected.summary != "" && finding.Summary != expected.summary {
+					t.Errorf("expected summary %q, got %q", expected.summary, finding.Summary)
+				}
+				if expected.advisoryType != "" && finding.Type != expected.advisoryType {
+					t.Errorf("expected type %s, got %s", expected.advisoryType, finding.Type)
+				}
+				if expected.aliases != nil && !slices.Equal(finding.Aliases, expected.aliases) {
+					t.Errorf("expected aliases %v, got %v", expected.aliases, finding.Aliases)
+				}
+				if expected.hasCvss != (finding.Cvss != nil) {
+					t.Errorf("expected cvss %t, got %+v", expected.hasCvss, finding.Cvss)
+				}
+
+				var packageNames []string
+				for _, vulnerability := range finding.Vulnerabilities {
+					packageNames = append(packageNames, vulnerability.Package.Name)
+				}
+				if !slices.Equal(packageNames, expected.vulnerabilities) {
+					t.Errorf("expected vulnerable packages %v, got %v", expected.vulnerabilities, packageNames)
+				}
+			}
+		})
+	}
+}
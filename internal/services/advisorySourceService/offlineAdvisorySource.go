@@ -0,0 +1,83 @@
+package advisorysourceservice
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	advisorydatabase "github.com/RobsonDevCode/deepscan/internal/advisoryDatabase"
+	"github.com/RobsonDevCode/deepscan/internal/clients/models"
+	advisorysources "github.com/RobsonDevCode/deepscan/internal/constants/advisorySources"
+)
+
+// OfflineAdvisorySource resolves findings from the imported advisory database without any network calls
+type OfflineAdvisorySource struct {
+	store    advisorydatabase.AdvisoryDatabaseStoreService
+	load     sync.Once
+	database *advisorydatabase.AdvisoryDatabase
+	loadErr  error
+}
+
+func NewOfflineAdvisorySource(store advisorydatabase.AdvisoryDatabaseStoreService) *OfflineAdvisorySource {
+	return &OfflineAdvisorySource{
+		store: store,
+	}
+}
+
+func (o *OfflineAdvisorySource) GetPackagesInfo(ecosystem string, packageAndVersions map[string]string, ctx context.Context) ([]models.ScannedPackage, error) {
+	return o.lookup(ecosystem, packageAndVersions, time.Time{})
+}
+
+func (o *OfflineAdvisorySource) GetPackagesInfoUpdatedSince(ecosystem string, packageAndVersions map[string]string, since time.Time, ctx context.Context) ([]models.ScannedPackage, error) {
+	return o.lookup(ecosystem, packageAndVersions, since)
+}
+
+func (o *OfflineAdvisorySource) GetAdvisoryDatabaseTimestamp(ctx context.Context) (time.Time, error) {
+	database, err := o.getDatabase()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return database.BuildDate, nil
+}
+
+func (o *OfflineAdvisorySource) lookup(ecosystem string, packageAndVersions map[string]string, modifiedSince time.Time) ([]models.ScannedPackage, error) {
+	database, err := o.getDatabase()
+	if err != nil {
+		return nil, err
+	}
+
+	osvEcosystem := ToOsvEcosystem(ecosystem)
+	seen := make(map[string]bool)
+
+	var result []models.ScannedPackage
+	for packageName := range packageAndVersions {
+		for _, vulnerability := range database.Lookup(osvEcosystem, packageName) {
+			if seen[vulnerability.Id] {
+				continue
+			}
+			seen[vulnerability.Id] = true
+
+			if !modifiedSince.IsZero() && vulnerability.Modified.Before(modifiedSince) {
+				continue
+			}
+
+			// same range matching as the osv backend, the api just does the first pass for us online
+			scannedPackage, ok := MapOsvVulnerability(vulnerability, ecosystem, packageAndVersions)
+			if ok {
+				scannedPackage.Source = advisorysources.Offline
+				result = append(result, scannedPackage)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (o *OfflineAdvisorySource) getDatabase() (*advisorydatabase.AdvisoryDatabase, error) {
+	o.load.Do(func() {
+		o.database, o.loadErr = o.store.Load()
+	})
+
+	return o.database, o.loadErr
+}
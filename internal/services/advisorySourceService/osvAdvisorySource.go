@@ -0,0 +1,258 @@
+package advisorysourceservice
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/RobsonDevCode/deepscan/internal/clients/models"
+	osvmodels "github.com/RobsonDevCode/deepscan/internal/clients/models/osv"
+	osvclient "github.com/RobsonDevCode/deepscan/internal/clients/osvClient"
+	advisorysources "github.com/RobsonDevCode/deepscan/internal/constants/advisorySources"
+	advisorytypes "github.com/RobsonDevCode/deepscan/internal/constants/advisoryTypes"
+	"github.com/RobsonDevCode/deepscan/internal/cvss"
+	ecosystemconstants "github.com/RobsonDevCode/deepscan/internal/scanner/constants/ecosystem"
+	"github.com/RobsonDevCode/deepscan/internal/versioning"
+	"golang.org/x/sync/errgroup"
+)
+
+// osv only returns ids from a batch query so we limit how many vulnerabilities we fetch at once
+const maxConcurrentVulnerabilityRequests = 10
+
+const (
+	githubAdvisoryUrl   = "https://github.com/advisories/"
+	osvVulnerabilityUrl = "https://osv.dev/vulnerability/"
+)
+
+type OsvAdvisorySource struct {
+	osvClient osvclient.OsvClientService
+}
+
+func NewOsvAdvisorySource(osvClient osvclient.OsvClientService) *OsvAdvisorySource {
+	return &OsvAdvisorySource{
+		osvClient: osvClient,
+	}
+}
+
+func (o *OsvAdvisorySource) GetPackagesInfo(ecosystem string, packageAndVersions map[string]string, ctx context.Context) ([]models.ScannedPackage, error) {
+	return o.query(ecosystem, packageAndVersions, time.Time{}, ctx)
+}
+
+func (o *OsvAdvisorySource) GetPackagesInfoUpdatedSince(ecosystem string, packageAndVersions map[string]string, since time.Time, ctx context.Context) ([]models.ScannedPackage, error) {
+	return o.query(ecosystem, packageAndVersions, since, ctx)
+}
+
+// GetAdvisoryDatabaseTimestamp osv is always live so we treat now as the database timestamp
+// and let the modified dates decide whether anything changed
+func (o *OsvAdvisorySource) GetAdvisoryDatabaseTimestamp(ctx context.Context) (time.Time, error) {
+	return time.Now().UTC(), nil
+}
+
+func (o *OsvAdvisorySource) query(ecosystem string, packageAndVersions map[string]string, modifiedSince time.Time, ctx context.Context) ([]models.ScannedPackage, error) {
+	if len(packageAndVersions) == 0 {
+		return nil, nil
+	}
+
+	osvEcosystem := ToOsvEcosystem(ecosystem)
+	var queries []osvmodels.Query
+	for packageName, version := range packageAndVersions {
+		query := osvmodels.Query{
+			Package: osvmodels.Package{Name: packageName, Ecosystem: osvEcosystem},
+		}
+		if version != "" && version != "0.0.0" {
+			query.Version = version
+		}
+		queries = append(queries, query)
+	}
+
+	results, err := o.osvClient.QueryBatch(queries, ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error querying osv: %w", err)
+	}
+
+	var ids []string
+	for _, result := range results {
+		for _, vuln := range result.Vulns {
+			if !modifiedSince.IsZero() && vuln.Modified.Before(modifiedSince) {
+				continue
+			}
+
+			if !slices.Contains(ids, vuln.Id) {
+				ids = append(ids, vuln.Id)
+			}
+		}
+	}
+
+	var mu sync.Mutex
+	var scannedPackages []models.ScannedPackage
+	group, gCtx := errgroup.WithContext(ctx)
+	group.SetLimit(maxConcurrentVulnerabilityRequests)
+
+	for _, id := range ids {
+		group.Go(func() error {
+			vulnerability, err := o.osvClient.GetVulnerability(id, gCtx)
+			if err != nil {
+				return err
+			}
+
+			scannedPackage, ok := MapOsvVulnerability(vulnerability, ecosystem, packageAndVersions)
+			if !ok {
+				return nil
+			}
+
+			mu.Lock()
+			scannedPackages = append(scannedPackages, scannedPackage)
+			mu.Unlock()
+
+			return nil
+		})
+	}
+
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	return scannedPackages, nil
+}
+
+// MapOsvVulnerability normalises an osv record into the same shape we get back from github,
+// keeping only the affected packages whose installed version falls inside an affected range
+func MapOsvVulnerability(vulnerability osvmodels.Vulnerability, ecosystem string, packageAndVersions map[string]string) (models.ScannedPackage, bool) {
+	osvEcosystem := ToOsvEcosystem(ecosystem)
+
+	var vulnerabilities []models.Vulnerability
+	for _, affected := range vulnerability.Affected {
+		if !strings.EqualFold(affected.Package.Ecosystem, osvEcosystem) {
+			continue
+		}
+
+		packageName, version, ok := lookupPackage(packageAndVersions, affected.Package.Name, ecosystem)
+		if !ok || !versioning.IsAffected(version, affected) {
+			continue
+		}
+
+		vulnerabilities = append(vulnerabilities, models.Vulnerability{
+			CurrentVersion:         version,
+			Package:                models.Package{Ecosystem: ecosystem, Name: packageName},
+			VulnerableVersionRange: versioning.DescribeRanges(affected),
+			FirstPatchedVersion:    versioning.FirstPatchedVersion(version, affected),
+		})
+	}
+
+	if len(vulnerabilities) == 0 {
+		return models.ScannedPackage{}, false
+	}
+
+	scannedPackage := models.ScannedPackage{
+		Aliases:         appendAliases(nil, append([]string{vulnerability.Id}, vulnerability.Aliases...)...),
+		Source:          advisorysources.Osv,
+		Summary:         vulnerability.Summary,
+		Description:     vulnerability.Details,
+		Severity:        mapOsvSeverity(vulnerability.DatabaseSpecific.Severity),
+		Cvss:            mapOsvCvss(vulnerability.Severity),
+		UpdatedAt:       vulnerability.Modified,
+		PublishedAt:     vulnerability.Published,
+		WithdrawnAt:     vulnerability.Withdrawn,
+		Vulnerabilities: vulnerabilities,
+	}
+
+	for _, alias := range scannedPackage.Aliases {
+		identifierType, _, _ := strings.Cut(alias, "-")
+		scannedPackage.Identifiers = append(scannedPackage.Identifiers, models.Identifier{Type: identifierType, Value: alias})
+
+		if scannedPackage.GhsaId == "" && strings.HasPrefix(alias, "GHSA-") {
+			scannedPackage.GhsaId = alias
+		}
+		if scannedPackage.CveId == "" && strings.HasPrefix(alias, "CVE-") {
+			scannedPackage.CveId = alias
+		}
+	}
+
+	for _, cweId := range vulnerability.DatabaseSpecific.CweIds {
+		scannedPackage.Cwes = append(scannedPackage.Cwes, models.Cwe{CweId: cweId})
+	}
+
+	// link to github where we can as thats where most of our teams already look
+	if scannedPackage.GhsaId != "" {
+		scannedPackage.HtmlUrl = githubAdvisoryUrl + scannedPackage.GhsaId
+	} else {
+		scannedPackage.HtmlUrl = osvVulnerabilityUrl + vulnerability.Id
+	}
+
+	// openssf malicious packages are published to osv with a MAL- prefix
+	if slices.ContainsFunc(scannedPackage.Aliases, func(alias string) bool { return strings.HasPrefix(alias, "MAL-") }) {
+		scannedPackage.Type = advisorytypes.Malware
+	}
+
+	if scannedPackage.Severity == "unknown" && scannedPackage.Cvss != nil {
+		scannedPackage.Severity = cvss.Severity(scannedPackage.Cvss.Score)
+	}
+
+	return scannedPackage, true
+}
+
+func mapOsvCvss(severities []osvmodels.Severity) *models.Cvss {
+	for _, severity := range severities {
+		if severity.Type != "CVSS_V3" {
+			continue
+		}
+
+		score, ok := cvss.BaseScore(severity.Score)
+		if !ok {
+			continue
+		}
+
+		return &models.Cvss{
+			Score:        score,
+			VectorString: severity.Score,
+		}
+	}
+
+	return nil
+}
+
+func ToOsvEcosystem(ecosystem string) string {
+	switch ecosystem {
+	case ecosystemconstants.Nuget:
+		return "NuGet"
+	default:
+		return ecosystem
+	}
+}
+
+// lookupPackage nuget package ids are case insensitive so the casing osv uses may not match the csproj
+func lookupPackage(packageAndVersions map[string]string, name string, ecosystem string) (string, string, bool) {
+	if version, ok := packageAndVersions[name]; ok {
+		return name, version, true
+	}
+
+	if ecosystem != ecosystemconstants.Nuget {
+		return "", "", false
+	}
+
+	for packageName, version := range packageAndVersions {
+		if strings.EqualFold(packageName, name) {
+			return packageName, version, true
+		}
+	}
+
+	return "", "", false
+}
+
+func mapOsvSeverity(severity string) string {
+	switch strings.ToLower(severity) {
+	case "critical":
+		return "critical"
+	case "high":
+		return "high"
+	case "moderate", "medium":
+		return "medium"
+	case "low":
+		return "low"
+	default:
+		return "unknown"
+	}
+}
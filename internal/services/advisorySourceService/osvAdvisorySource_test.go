@@ -0,0 +1,44 @@
+package advisorysourceservice
+
+import (
+	"testing"
+
+	osvmodels "github.com/RobsonDevCode/deepscan/internal/clients/models/osv"
+	advisorytypes "github.com/RobsonDevCode/deepscan/internal/constants/advisoryTypes"
+	ecosystemconstants "github.com/RobsonDevCode/deepscan/internal/scanner/constants/ecosystem"
+)
+
+func TestMapOsvVulnerabilityMarksMalware(t *testing.T) {
+	tests := []struct {
+		name         string
+		id           string
+		aliases      []string
+		expectedType string
+	}{
+		{name: "openssf malicious package", id: "MAL-2024-1234", expectedType: advisorytypes.Malware},
+		{name: "ghsa aliased to a malicious package", id: "GHSA-aaaa-bbbb-cccc", aliases: []string{"MAL-2024-1234"}, expectedType: advisorytypes.Malware},
+		{name: "regular vulnerability", id: "GHSA-aaaa-bbbb-cccc", aliases: []string{"CVE-2024-0001"}},
+		{name: "mal only counts as a prefix", id: "PYSEC-2024-1", aliases: []string{"CVE-2024-MAL-1"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			vulnerability := osvmodels.Vulnerability{
+				Id:      test.id,
+				Aliases: test.aliases,
+				Affected: []osvmodels.Affected{{
+					Package: osvmodels.Package{Name: "evil-pkg", Ecosystem: "npm"},
+					Ranges:  []osvmodels.Range{{Type: "SEMVER", Events: []osvmodels.Event{{Introduced: "0"}}}},
+				}},
+			}
+
+			scannedPackage, ok := MapOsvVulnerability(vulnerability, ecosystemconstants.Npm, map[string]string{"evil-pkg": "1.0.0"})
+			if !ok {
+				t.Fatalf("expected evil-pkg 1.0.0 to be affected")
+			}
+			if scannedPackage.Type != test.expectedType {
+				t.Errorf("expected type %q, got %q", test.expectedType, scannedPackage.Type)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package azurerepositoryservice
+
+import (
+	"context"
+	"fmt"
+
+	azuredevopsclient "github.com/RobsonDevCode/deepscan/internal/clients/azureDevopsClient"
+	"github.com/RobsonDevCode/deepscan/internal/clients/mapper"
+	cmdmodels "github.com/RobsonDevCode/deepscan/internal/thirdPartyCommands/models"
+)
+
+type AzureRepositoryService interface {
+	GetRepos(orgUrl string, project string, ctx context.Context) ([]cmdmodels.Repository, error)
+}
+
+type AzureRepositoryRetrival struct {
+	azureClient azuredevopsclient.AzureDevopsClientService
+}
+
+func NewAzureRepositoryRetrivalService(azureClient azuredevopsclient.AzureDevopsClientService) AzureRepositoryRetrival {
+	return AzureRepositoryRetrival{
+		azureClient: azureClient,
+	}
+}
+
+func (a *AzureRepositoryRetrival) GetRepos(orgUrl string, project string, ctx context.Context) ([]cmdmodels.Repository, error) {
+	azureRepos, err := a.azureClient.GetRepositories(orgUrl, project, ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting repos: %w", err)
+	}
+
+	var result []cmdmodels.Repository
+	for _, repo := range mapper.MapAzureRepositories(azureRepos, project == azuredevopsclient.AllProjects) {
+		if repo.IsDisabled {
+			continue
+		}
+		result = append(result, repo)
+	}
+
+	return result, nil
+}
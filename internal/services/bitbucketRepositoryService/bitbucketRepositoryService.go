@@ -0,0 +1,51 @@
+package bitbucketrepositoryservice
+
+import (
+	"context"
+	"fmt"
+
+	bitbucketclient "github.com/RobsonDevCode/deepscan/internal/clients/bitbucketClient"
+	"github.com/RobsonDevCode/deepscan/internal/clients/mapper"
+	cmdmodels "github.com/RobsonDevCode/deepscan/internal/thirdPartyCommands/models"
+)
+
+type BitbucketRepositoryService interface {
+	GetCloudRepos(workspace string, ctx context.Context) ([]cmdmodels.Repository, error)
+	GetDataCenterRepos(instanceUrl string, projectKey string, ctx context.Context) ([]cmdmodels.Repository, error)
+}
+
+type BitbucketRepositoryRetrival struct {
+	bitbucketClient bitbucketclient.BitbucketClientService
+}
+
+func NewBitbucketRepositoryRetrivalService(bitbucketClient bitbucketclient.BitbucketClientService) BitbucketRepositoryRetrival {
+	return BitbucketRepositoryRetrival{
+		bitbucketClient: bitbucketClient,
+	}
+}
+
+func (b *BitbucketRepositoryRetrival) GetCloudRepos(workspace string, ctx context.Context) ([]cmdmodels.Repository, error) {
+	cloudRepos, err := b.bitbucketClient.GetWorkspaceRepositories(workspace, ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting repos: %w", err)
+	}
+
+	return mapper.MapBitbucketCloudRepositories(cloudRepos), nil
+}
+
+func (b *BitbucketRepositoryRetrival) GetDataCenterRepos(instanceUrl string, projectKey string, ctx context.Context) ([]cmdmodels.Repository, error) {
+	dataCenterRepos, err := b.bitbucketClient.GetProjectRepositories(instanceUrl, projectKey, ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting repos: %w", err)
+	}
+
+	var result []cmdmodels.Repository
+	for _, repo := range mapper.MapBitbucketDataCenterRepositories(dataCenterRepos) {
+		if repo.IsDisabled {
+			continue
+		}
+		result = append(result, repo)
+	}
+
+	return result, nil
+}
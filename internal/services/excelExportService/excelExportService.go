@@ -4,21 +4,21 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"time"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/RobsonDevCode/deepscan/internal/clients/models"
 	"github.com/RobsonDevCode/deepscan/internal/constants/exportExcelOptions"
+	exportformats "github.com/RobsonDevCode/deepscan/internal/constants/exportFormats"
 	"github.com/RobsonDevCode/deepscan/internal/constants/tableHeaders"
+	"github.com/RobsonDevCode/deepscan/internal/extensions"
 	"github.com/xuri/excelize/v2"
 )
 
-const saveFileTo = "./export"
 const packageSheetName = "Package Vulnerabilities"
 
-func ExportPackageTable(packages []models.ScannedPackage, isFullScan bool) error {
-	if err := os.MkdirAll(saveFileTo, 0755); err != nil {
-		return fmt.Errorf("error creating directory %s, %w", saveFileTo, err)
+func ExportPackageTable(packages []models.ScannedPackage, isFullScan bool, outDirectory string) error {
+	if err := os.MkdirAll(outDirectory, 0755); err != nil {
+		return fmt.Errorf("error creating directory %s, %w", outDirectory, err)
 	}
 
 	file := excelize.NewFile()
@@ -30,13 +30,18 @@ func ExportPackageTable(packages []models.ScannedPackage, isFullScan bool) error
 		file.SetCellValue(packageSheetName, cell, header)
 	}
 
-	//excelName identifier
-	var name string
-	for i, pkg := range packages {
-		row := i + 2 // excel is 1 index and skip headers
+	linkStyle, err := file.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Color: "0563C1", Underline: "single"},
+	})
+	if err != nil {
+		return fmt.Errorf("error creating link style, %w", err)
+	}
 
-		if name == "" && !isFullScan {
-			name = pkg.ServiceName
+	row := 2 // excel is 1 index and skip headers
+	for _, pkg := range packages {
+		var cvssVector string
+		if pkg.Cvss != nil {
+			cvssVector = pkg.Cvss.VectorString
 		}
 
 		for _, vuln := range pkg.Vulnerabilities {
@@ -45,24 +50,40 @@ func ExportPackageTable(packages []models.ScannedPackage, isFullScan bool) error
 				pkg.ProjectName,
 				vuln.Package.Name,
 				vuln.CurrentVersion,
+				extensions.AdvisoryId(pkg),
+				pkg.Type,
+				pkg.CveId,
 				pkg.Summary,
 				pkg.Description,
-				vuln.FirstPatchedVersion,
 				pkg.Severity,
-				pkg.GithubReviewedAt.Format("2006-01-02"),
+				extensions.FormatCvss(pkg),
+				cvssVector,
+				extensions.FormatCwes(pkg),
+				pkg.RiskScore,
+				pkg.Epss,
+				extensions.FormatBool(pkg.KnownExploited),
+				extensions.FormatDependencyDepth(vuln),
+				vuln.FirstPatchedVersion,
+				extensions.FormatDate(pkg.PublishedAt),
+				extensions.FormatDate(pkg.GithubReviewedAt),
+				pkg.AdvisoryDatabase,
+				pkg.CommitSha,
+				pkg.Profile,
+				pkg.Provider,
 			}
 
 			file.SetSheetRow(packageSheetName, fmt.Sprintf("A%d", row), &rowData)
+
+			if pkg.HtmlUrl != "" {
+				advisoryCell := fmt.Sprintf("E%d", row)
+				file.SetCellHyperLink(packageSheetName, advisoryCell, pkg.HtmlUrl, "External")
+				file.SetCellStyle(packageSheetName, advisoryCell, advisoryCell, linkStyle)
+			}
 			row++
 		}
 	}
 
-	if isFullScan {
-		name = "full_scan"
-	}
-
-	fileName := fmt.Sprintf("package_%s_vun_%s.xlsx", name, time.Now().Format("2006-01-02T15-04-05"))
-	fullPath := filepath.Join(saveFileTo, fileName)
+	fullPath := filepath.Join(outDirectory, extensions.ExportFileName(packages, isFullScan, exportformats.Xlsx))
 
 	if err := file.SaveAs(fullPath); err != nil {
 		return fmt.Errorf("failed to save excel to %s, %w", fullPath, err)
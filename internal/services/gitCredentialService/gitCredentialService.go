@@ -0,0 +1,73 @@
+package gitcredentialservice
+
+import (
+	"context"
+	"fmt"
+
+	azuredevopsclient "github.com/RobsonDevCode/deepscan/internal/clients/azureDevopsClient"
+	bitbucketclient "github.com/RobsonDevCode/deepscan/internal/clients/bitbucketClient"
+	giteaclient "github.com/RobsonDevCode/deepscan/internal/clients/giteaClient"
+	gitlabclient "github.com/RobsonDevCode/deepscan/internal/clients/gitlabClient"
+	supportedproviders "github.com/RobsonDevCode/deepscan/internal/constants/supportedProviders"
+	gitubauthenticationservice "github.com/RobsonDevCode/deepscan/internal/services/gitubAuthenticationService"
+	githubcommands "github.com/RobsonDevCode/deepscan/internal/thirdPartyCommands/githubCommands"
+)
+
+type GitCredentialService interface {
+	GetCredentials(provider string, owner string, ctx context.Context) (*githubcommands.Credentials, error)
+}
+
+// GitCredentialProvider reuses the token each provider client already reads so https clones need no extra setup
+type GitCredentialProvider struct {
+	githubAuth        gitubauthenticationservice.GithubAuthenticatorService
+	gitlabClient      gitlabclient.GitlabClientService
+	azureDevopsClient azuredevopsclient.AzureDevopsClientService
+	bitbucketClient   bitbucketclient.BitbucketClientService
+	giteaClient       giteaclient.GiteaClientService
+}
+
+func NewGitCredentialProvider(githubAuth gitubauthenticationservice.GithubAuthenticatorService,
+	gitlabClient gitlabclient.GitlabClientService,
+	azureDevopsClient azuredevopsclient.AzureDevopsClientService,
+	bitbucketClient bitbucketclient.BitbucketClientService,
+	giteaClient giteaclient.GiteaClientService) *GitCredentialProvider {
+	return &GitCredentialProvider{
+		githubAuth:        githubAuth,
+		gitlabClient:      gitlabClient,
+		azureDevopsClient: azureDevopsClient,
+		bitbucketClient:   bitbucketClient,
+		giteaClient:       giteaClient,
+	}
+}
+
+func (g *GitCredentialProvider) GetCredentials(provider string, owner string, ctx context.Context) (*githubcommands.Credentials, error) {
+	var username, password string
+	var err error
+
+	switch provider {
+	case supportedproviders.Github:
+		accessToken, authErr := g.githubAuth.AuthenticateOwner(owner, ctx)
+		// github accepts oauth and app tokens as the password for any username, x-access-token is what it documents
+		username, password, err = "x-access-token", accessToken.Token, authErr
+
+	case supportedproviders.Gitlab:
+		username, password, err = g.gitlabClient.GitCredentials()
+
+	case supportedproviders.Azure:
+		username, password, err = g.azureDevopsClient.GitCredentials()
+
+	case supportedproviders.BitbucketCloud, supportedproviders.BitbucketDataCenter:
+		username, password, err = g.bitbucketClient.GitCredentials()
+
+	case supportedproviders.Gitea, supportedproviders.Forgejo:
+		username, password, err = g.giteaClient.GitCredentials()
+
+	default:
+		return nil, fmt.Errorf("https cloning isnt supported for %s", provider)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting %s credentials to clone with: %w", provider, err)
+	}
+
+	return &githubcommands.Credentials{Username: username, Password: password}, nil
+}
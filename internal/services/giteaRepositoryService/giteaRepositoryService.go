@@ -0,0 +1,41 @@
+package gitearepositoryservice
+
+import (
+	"context"
+	"fmt"
+
+	giteaclient "github.com/RobsonDevCode/deepscan/internal/clients/giteaClient"
+	"github.com/RobsonDevCode/deepscan/internal/clients/mapper"
+	cmdmodels "github.com/RobsonDevCode/deepscan/internal/thirdPartyCommands/models"
+)
+
+type GiteaRepositoryService interface {
+	GetRepos(instanceUrl string, owner string, ctx context.Context) ([]cmdmodels.Repository, error)
+}
+
+type GiteaRepositoryRetrival struct {
+	giteaClient giteaclient.GiteaClientService
+}
+
+func NewGiteaRepositoryRetrivalService(giteaClient giteaclient.GiteaClientService) GiteaRepositoryRetrival {
+	return GiteaRepositoryRetrival{
+		giteaClient: giteaClient,
+	}
+}
+
+func (g *GiteaRepositoryRetrival) GetRepos(instanceUrl string, owner string, ctx context.Context) ([]cmdmodels.Repository, error) {
+	giteaRepos, err := g.giteaClient.GetOwnerRepositories(instanceUrl, owner, ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting repos: %w", err)
+	}
+
+	var result []cmdmodels.Repository
+	for _, repo := range mapper.MapGiteaRepositories(giteaRepos) {
+		if repo.IsDisabled {
+			continue
+		}
+		result = append(result, repo)
+	}
+
+	return result, nil
+}
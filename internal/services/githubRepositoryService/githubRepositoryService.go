@@ -3,9 +3,13 @@ package githubrepositoryservice
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/RobsonDevCode/deepscan/internal/clients"
 	"github.com/RobsonDevCode/deepscan/internal/clients/mapper"
+	githubreposmodels "github.com/RobsonDevCode/deepscan/internal/clients/models/repos"
+	"github.com/RobsonDevCode/deepscan/internal/configuration"
 	gitubauthenticationservice "github.com/RobsonDevCode/deepscan/internal/services/gitubAuthenticationService"
 	cmdmodels "github.com/RobsonDevCode/deepscan/internal/thirdPartyCommands/models"
 )
@@ -17,26 +21,79 @@ type GitRepositoryService interface {
 type GitHubRepositoryRetrival struct {
 	githubClient clients.GithubClientService
 	githubAuth   gitubauthenticationservice.GithubAuthenticatorService
+	filters      configuration.GithubRepositoryFilters
 }
 
-func NewGithubRepositoryRetrivalService(githubClient clients.GithubClientService, githubAuth gitubauthenticationservice.GithubAuthenticatorService) GitHubRepositoryRetrival {
+func NewGithubRepositoryRetrivalService(githubClient clients.GithubClientService, githubAuth gitubauthenticationservice.GithubAuthenticatorService,
+	filters configuration.GithubRepositoryFilters) GitHubRepositoryRetrival {
 	return GitHubRepositoryRetrival{
 		githubClient: githubClient,
 		githubAuth:   githubAuth,
+		filters:      filters,
 	}
 }
 
 func (g *GitHubRepositoryRetrival) GetRepos(profile string, ctx context.Context) ([]cmdmodels.Repository, error) {
-	ghAccessToken, err := g.githubAuth.AuthenticateUser(ctx)
+	ghAccessToken, err := g.githubAuth.AuthenticateOwner(profile, ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	ghRepos, err := g.githubClient.GetRepositories(ghAccessToken.Token, ctx)
+	ghRepos, err := g.githubClient.GetRepositories(profile, ghAccessToken.Token, ctx)
 	if err != nil {
 		return nil, fmt.Errorf("error getting repos: %w", err)
 	}
 
+	ghRepos = slices.DeleteFunc(ghRepos, func(repo githubreposmodels.GithubRepository) bool {
+		return !g.matchesFilters(repo)
+	})
+
 	result := mapper.Map(ghRepos)
 	return result, nil
 }
+
+func (g *GitHubRepositoryRetrival) matchesFilters(repo githubreposmodels.GithubRepository) bool {
+	if repo.Archived && !g.filters.IncludeArchived {
+		return false
+	}
+
+	if repo.Fork && !g.filters.IncludeForks {
+		return false
+	}
+
+	visibility := strings.ToLower(g.filters.Visibility)
+	if visibility != "" && visibility != "all" && !strings.EqualFold(repoVisibility(repo), visibility) {
+		return false
+	}
+
+	if len(g.filters.Topics) > 0 && !slices.ContainsFunc(repo.Topics, func(topic string) bool {
+		return containsFold(g.filters.Topics, topic)
+	}) {
+		return false
+	}
+
+	if len(g.filters.Languages) > 0 && !containsFold(g.filters.Languages, repo.Language) {
+		return false
+	}
+
+	return true
+}
+
+// repoVisibility older github enterprise servers dont send visibility so we fall back to the private flag
+func repoVisibility(repo githubreposmodels.GithubRepository) string {
+	if repo.Visibility != "" {
+		return repo.Visibility
+	}
+
+	if repo.Private {
+		return "private"
+	}
+
+	return "public"
+}
+
+func containsFold(values []string, value string) bool {
+	return slices.ContainsFunc(values, func(v string) bool {
+		return strings.EqualFold(v, value)
+	})
+}
@@ -0,0 +1,33 @@
+package gitlabrepositoryservice
+
+import (
+	"context"
+	"fmt"
+
+	gitlabclient "github.com/RobsonDevCode/deepscan/internal/clients/gitlabClient"
+	"github.com/RobsonDevCode/deepscan/internal/clients/mapper"
+	cmdmodels "github.com/RobsonDevCode/deepscan/internal/thirdPartyCommands/models"
+)
+
+type GitlabRepositoryService interface {
+	GetRepos(instanceUrl string, group string, ctx context.Context) ([]cmdmodels.Repository, error)
+}
+
+type GitlabRepositoryRetrival struct {
+	gitlabClient gitlabclient.GitlabClientService
+}
+
+func NewGitlabRepositoryRetrivalService(gitlabClient gitlabclient.GitlabClientService) GitlabRepositoryRetrival {
+	return GitlabRepositoryRetrival{
+		gitlabClient: gitlabClient,
+	}
+}
+
+func (g *GitlabRepositoryRetrival) GetRepos(instanceUrl string, group string, ctx context.Context) ([]cmdmodels.Repository, error) {
+	projects, err := g.gitlabClient.GetGroupProjects(instanceUrl, group, ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting repos: %w", err)
+	}
+
+	return mapper.MapGitlabProjects(projects), nil
+}
@@ -0,0 +1,135 @@
+package gitubauthenticationservice
+
+import (
+	"context"
+	"crypto/rsa"
+	"fmt"
+	"strings"
+	"time"
+
+	cache "github.com/RobsonDevCode/deepscan/internal/caching"
+	githubappclient "github.com/RobsonDevCode/deepscan/internal/clients/githubAppClient"
+	authenticationmodels "github.com/RobsonDevCode/deepscan/internal/clients/models/githubAuthentication"
+	"github.com/RobsonDevCode/deepscan/internal/configuration"
+)
+
+// installation tokens last an hour, dropping them this early means one never expires mid scan
+const installationTokenRefreshWindow = 5 * time.Minute
+
+// GithubAppAuthenticator authenticates as a github app so scans can run without anyone at a browser,
+// each owner the app is installed on gets its own installation token
+type GithubAppAuthenticator struct {
+	githubAppClient githubappclient.GithubAppClientService
+	cache           *cache.Cache
+	appId           string
+	privateKey      *rsa.PrivateKey
+	advisoryOwner   string
+}
+
+func NewGithubAppAuthenticator(githubAppClient githubappclient.GithubAppClientService, config *configuration.Config,
+	cache *cache.Cache) (*GithubAppAuthenticator, error) {
+	privateKey, err := readPrivateKey(config.GithubAppSettings.PrivateKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GithubAppAuthenticator{
+		githubAppClient: githubAppClient,
+		cache:           cache,
+		appId:           config.GithubAppSettings.AppId,
+		privateKey:      privateKey,
+		advisoryOwner:   config.GithubAppSettings.AdvisoryOwner,
+	}, nil
+}
+
+// IsGithubAppConfigured placeholders from the default config count as not set
+func IsGithubAppConfigured(settings configuration.GithubAppSettings) bool {
+	isSet := func(value string) bool {
+		return value != "" && !strings.HasPrefix(value, "{")
+	}
+
+	return isSet(settings.AppId) && isSet(settings.PrivateKeyFile)
+}
+
+// AuthenticateUser without an owner we use the advisory owner, falling back to the apps first installation
+func (g *GithubAppAuthenticator) AuthenticateUser(ctx context.Context) (authenticationmodels.GithubAccessToken, error) {
+	return g.AuthenticateOwner(g.advisoryOwner, ctx)
+}
+
+func (g *GithubAppAuthenticator) AuthenticateOwner(owner string, ctx context.Context) (authenticationmodels.GithubAccessToken, error) {
+	response, err := g.cache.GetOrCreate("github-app-token-"+owner, func(entry *cache.CacheEntry) (interface{}, error) {
+		appJwt, err := signAppJwt(g.appId, g.privateKey, time.Now())
+		if err != nil {
+			return nil, err
+		}
+
+		installationId, err := g.installationId(owner, appJwt, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		installationToken, err := g.githubAppClient.CreateInstallationToken(installationId, appJwt, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error creating installation token: %w", err)
+		}
+
+		entry.Expiration = installationToken.ExpiresAt.Add(-installationTokenRefreshWindow)
+		return authenticationmodels.GithubAccessToken{Token: installationToken.Token, TokenType: "token"}, nil
+	})
+	if err != nil {
+		return authenticationmodels.GithubAccessToken{}, fmt.Errorf("error authenticating github app: %w", err)
+	}
+
+	accessToken, ok := response.(authenticationmodels.GithubAccessToken)
+	if !ok {
+		return authenticationmodels.GithubAccessToken{}, fmt.Errorf("unexpected response type when converting response")
+	}
+
+	return accessToken, nil
+}
+
+// Login minting a token proves the app id, key and installation all work
+func (g *GithubAppAuthenticator) Login(ctx context.Context) (authenticationmodels.GithubAccessToken, error) {
+	return g.AuthenticateUser(ctx)
+}
+
+func (g *GithubAppAuthenticator) Logout() error {
+	return fmt.Errorf("github app tokens are never stored, remove github_app_settings from the config to stop using the app")
+}
+
+func (g *GithubAppAuthenticator) CurrentToken(ctx context.Context) (authenticationmodels.GithubAccessToken, error) {
+	return g.AuthenticateUser(ctx)
+}
+
+func (g *GithubAppAuthenticator) Method() string {
+	return "github app " + g.appId
+}
+
+// SetNonInteractive app tokens never need anyone at a browser so theres nothing to turn off
+func (g *GithubAppAuthenticator) SetNonInteractive(nonInteractive bool) {}
+
+// AdvisoryToken advisories arent tied to an owner so any installation token can look them up
+func (g *GithubAppAuthenticator) AdvisoryToken(ctx context.Context) (string, error) {
+	accessToken, err := g.AuthenticateUser(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return accessToken.Token, nil
+}
+
+func (g *GithubAppAuthenticator) installationId(owner string, appJwt string, ctx context.Context) (int64, error) {
+	if owner != "" {
+		return g.githubAppClient.GetInstallationId(owner, appJwt, ctx)
+	}
+
+	installations, err := g.githubAppClient.GetInstallations(appJwt, ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error listing github app installations: %w", err)
+	}
+	if len(installations) == 0 {
+		return 0, fmt.Errorf("the github app isnt installed anywhere, install it on an org or set advisory_owner")
+	}
+
+	return installations[0].Id, nil
+}
@@ -0,0 +1,154 @@
+package gitubauthenticationservice
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cache "github.com/RobsonDevCode/deepscan/internal/caching"
+	githubappclient "github.com/RobsonDevCode/deepscan/internal/clients/githubAppClient"
+	"github.com/RobsonDevCode/deepscan/internal/configuration"
+)
+
+func TestAuthenticateOwnerRefreshesInstallationTokens(t *testing.T) {
+	tests := []struct {
+		name             string
+		tokenLifetime    time.Duration
+		expectedTokens   []string
+		expectedMintings int32
+	}{
+		{name: "cached for most of its hour", tokenLifetime: time.Hour, expectedTokens: []string{"ghs_1", "ghs_1"}, expectedMintings: 1},
+		{name: "cached just before the refresh window", tokenLifetime: installationTokenRefreshWindow + time.Minute, expectedTokens: []string{"ghs_1", "ghs_1"}, expectedMintings: 1},
+		{name: "refreshed inside the refresh window", tokenLifetime: installationTokenRefreshWindow - time.Minute, expectedTokens: []string{"ghs_1", "ghs_2"}, expectedMintings: 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			privateKey := generateKey(t)
+			var mintings atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				appJwt, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+				if !ok {
+					t.Errorf("expected the app jwt as a bearer token, got %q", r.Header.Get("Authorization"))
+				}
+				if claims := verifyAppJwt(t, appJwt, &privateKey.PublicKey); claims["iss"] != float64(123) {
+					t.Errorf("expected the app id as the issuer, got %v", claims["iss"])
+				}
+
+				switch r.URL.Path {
+				case "/orgs/acme/installation":
+					fmt.Fprint(w, `{"id":42}`)
+				case "/app/installations/42/access_tokens":
+					minting := mintings.Add(1)
+					w.WriteHeader(http.StatusCreated)
+					fmt.Fprintf(w, `{"token":"ghs_%d","expires_at":"%s"}`, minting, time.Now().Add(test.tokenLifetime).UTC().Format(time.RFC3339))
+				default:
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+			}))
+			defer server.Close()
+
+			authenticator := newTestAppAuthenticator(t, server.URL, "", x509.MarshalPKCS1PrivateKey(privateKey))
+			for _, expected := range test.expectedTokens {
+				accessToken, err := authenticator.AuthenticateOwner("acme", context.Background())
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if accessToken.Token != expected {
+					t.Errorf("expected %s, got %s", expected, accessToken.Token)
+				}
+			}
+
+			if got := mintings.Load(); got != test.expectedMintings {
+				t.Errorf("expected %d installation tokens minted, got %d", test.expectedMintings, got)
+			}
+		})
+	}
+}
+
+// installationsStub answers the installation lookups and mints a token named after whichever installation it was asked for
+func installationsStub(t *testing.T, installations string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/orgs/acme/installation":
+			fmt.Fprint(w, `{"id":42}`)
+		case r.URL.Path == "/app/installations":
+			fmt.Fprint(w, installations)
+		case strings.HasSuffix(r.URL.Path, "/access_tokens"):
+			installationId := strings.Split(r.URL.Path, "/")[3]
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"token":"ghs_%s","expires_at":"%s"}`, installationId, time.Now().Add(time.Hour).UTC().Format(time.RFC3339))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+}
+
+func TestAdvisoryTokenPicksInstallation(t *testing.T) {
+	tests := []struct {
+		name          string
+		advisoryOwner string
+		installations string
+		expectedToken string
+	}{
+		{name: "advisory owner set", advisoryOwner: "acme", expectedToken: "ghs_42"},
+		{name: "first installation without an advisory owner", installations: `[{"id":7},{"id":9}]`, expectedToken: "ghs_7"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			privateKey := generateKey(t)
+			server := installationsStub(t, test.installations)
+			defer server.Close()
+
+			authenticator := newTestAppAuthenticator(t, server.URL, test.advisoryOwner, x509.MarshalPKCS1PrivateKey(privateKey))
+			token, err := authenticator.AdvisoryToken(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if token != test.expectedToken {
+				t.Errorf("expected %s, got %s", test.expectedToken, token)
+			}
+		})
+	}
+}
+
+func TestAdvisoryTokenWithoutInstallations(t *testing.T) {
+	privateKey := generateKey(t)
+	server := installationsStub(t, `[]`)
+	defer server.Close()
+
+	authenticator := newTestAppAuthenticator(t, server.URL, "", x509.MarshalPKCS1PrivateKey(privateKey))
+	if _, err := authenticator.AdvisoryToken(context.Background()); err == nil || !strings.Contains(err.Error(), "set advisory_owner") {
+		t.Errorf("expected to be told to install the app or set advisory_owner, got %v", err)
+	}
+}
+
+func newTestAppAuthenticator(t *testing.T, baseUrl string, advisoryOwner string, pkcs1 []byte) *GithubAppAuthenticator {
+	config := &configuration.Config{}
+	config.GithubAppSettings = configuration.GithubAppSettings{
+		BaseUrl:        baseUrl + "/",
+		AppId:          "123",
+		PrivateKeyFile: writePem(t, "RSA PRIVATE KEY", pkcs1),
+		AdvisoryOwner:  advisoryOwner,
+	}
+
+	cacheInstance := &cache.Cache{}
+	githubAppClient, err := githubappclient.NewGithubAppClient(config, cacheInstance)
+	if err != nil {
+		t.Fatalf("unexpected error creating client: %v", err)
+	}
+
+	authenticator, err := NewGithubAppAuthenticator(githubAppClient, config, cacheInstance)
+	if err != nil {
+		t.Fatalf("unexpected error creating authenticator: %v", err)
+	}
+
+	return authenticator
+}
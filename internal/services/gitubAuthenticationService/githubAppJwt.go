@@ -0,0 +1,80 @@
+package gitubauthenticationservice
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// github rejects app jwts that live longer than 10 minutes
+const appJwtLifetime = 9 * time.Minute
+
+// readPrivateKey github hands out pkcs1 keys but a key converted to pkcs8 works too
+func readPrivateKey(file string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading github app private key: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("github app private key %s isnt pem encoded", file)
+	}
+
+	if privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return privateKey, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing github app private key: %w", err)
+	}
+
+	privateKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("github app private key %s isnt an rsa key", file)
+	}
+
+	return privateKey, nil
+}
+
+// signAppJwt builds the RS256 jwt the app endpoints take, issued a minute early to allow for clock drift
+func signAppJwt(appId string, privateKey *rsa.PrivateKey, now time.Time) (string, error) {
+	//app ids are numbers, anything else is the apps client id which github also accepts
+	var issuer any = appId
+	if id, err := strconv.ParseInt(appId, 10, 64); err == nil {
+		issuer = id
+	}
+
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", fmt.Errorf("error marshalling jwt header: %w", err)
+	}
+
+	claims, err := json.Marshal(map[string]any{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(appJwtLifetime).Unix(),
+		"iss": issuer,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error marshalling jwt claims: %w", err)
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("error signing github app jwt: %w", err)
+	}
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
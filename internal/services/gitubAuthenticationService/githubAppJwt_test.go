@@ -0,0 +1,161 @@
+package gitubauthenticationservice
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func generateKey(t *testing.T) *rsa.PrivateKey {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+
+	return privateKey
+}
+
+func writePem(t *testing.T, blockType string, der []byte) string {
+	file := filepath.Join(t.TempDir(), "app.pem")
+	if err := os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600); err != nil {
+		t.Fatalf("error writing key: %v", err)
+	}
+
+	return file
+}
+
+// verifyAppJwt checks the signature and returns the claims, failing the test when the jwt isnt one github would accept
+func verifyAppJwt(t *testing.T, appJwt string, publicKey *rsa.PublicKey) map[string]any {
+	parts := strings.Split(appJwt, ".")
+	if len(parts) != 3 {
+		t.Fatalf("expected a jwt with three parts, got %q", appJwt)
+	}
+
+	var header map[string]string
+	decodeJwtPart(t, parts[0], &header)
+	if header["alg"] != "RS256" || header["typ"] != "JWT" {
+		t.Errorf("expected an RS256 jwt header, got %v", header)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Fatalf("error decoding signature: %v", err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], signature); err != nil {
+		t.Errorf("jwt signature doesnt verify: %v", err)
+	}
+
+	var claims map[string]any
+	decodeJwtPart(t, parts[1], &claims)
+	return claims
+}
+
+func decodeJwtPart(t *testing.T, part string, target any) {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		t.Fatalf("error decoding jwt part: %v", err)
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		t.Fatalf("error unmarshalling jwt part: %v", err)
+	}
+}
+
+func TestSignAppJwt(t *testing.T) {
+	privateKey := generateKey(t)
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		appId          string
+		expectedIssuer any
+	}{
+		{name: "numeric app id", appId: "123456", expectedIssuer: float64(123456)},
+		{name: "client id", appId: "Iv23liExample", expectedIssuer: "Iv23liExample"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			appJwt, err := signAppJwt(test.appId, privateKey, now)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			claims := verifyAppJwt(t, appJwt, &privateKey.PublicKey)
+			if claims["iss"] != test.expectedIssuer {
+				t.Errorf("expected issuer %v, got %v", test.expectedIssuer, claims["iss"])
+			}
+			if claims["iat"] != float64(now.Add(-time.Minute).Unix()) {
+				t.Errorf("expected iat a minute before now, got %v", claims["iat"])
+			}
+			if claims["exp"] != float64(now.Add(appJwtLifetime).Unix()) {
+				t.Errorf("expected exp %s after now, got %v", appJwtLifetime, claims["exp"])
+			}
+		})
+	}
+}
+
+func TestReadPrivateKey(t *testing.T) {
+	privateKey := generateKey(t)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		t.Fatalf("error marshalling pkcs8 key: %v", err)
+	}
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("error generating ec key: %v", err)
+	}
+	ecPkcs8, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	if err != nil {
+		t.Fatalf("error marshalling ec key: %v", err)
+	}
+
+	notPem := filepath.Join(t.TempDir(), "app.pem")
+	if err := os.WriteFile(notPem, []byte("not a key"), 0600); err != nil {
+		t.Fatalf("error writing key: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		file     string
+		expected string
+	}{
+		{name: "pkcs1 as github hands out", file: writePem(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(privateKey))},
+		{name: "pkcs8", file: writePem(t, "PRIVATE KEY", pkcs8)},
+		{name: "not rsa", file: writePem(t, "PRIVATE KEY", ecPkcs8), expected: "isnt an rsa key"},
+		{name: "not pem", file: notPem, expected: "isnt pem encoded"},
+		{name: "missing", file: filepath.Join(t.TempDir(), "missing.pem"), expected: "error reading github app private key"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			key, err := readPrivateKey(test.file)
+			if test.expected != "" {
+				if err == nil || !strings.Contains(err.Error(), test.expected) {
+					t.Errorf("expected an error containing %q, got %v", test.expected, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !key.Equal(privateKey) {
+				t.Errorf("expected the key that was written")
+			}
+		})
+	}
+}
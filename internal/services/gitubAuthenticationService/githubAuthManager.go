@@ -0,0 +1,71 @@
+package gitubauthenticationservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/RobsonDevCode/deepscan/internal/clients"
+	authenticationmodels "github.com/RobsonDevCode/deepscan/internal/clients/models/githubAuthentication"
+)
+
+type GithubAuthManagerService interface {
+	Login(ctx context.Context) (authenticationmodels.AuthStatus, error)
+	Status(ctx context.Context) (authenticationmodels.AuthStatus, error)
+	Logout() error
+}
+
+// GithubAuthManager backs the auth command, it works the same whether github is signed into with the device flow or an app
+type GithubAuthManager struct {
+	authenticator GithubAuthenticatorService
+	githubClient  clients.GithubClientService
+}
+
+func NewGithubAuthManager(authenticator GithubAuthenticatorService, githubClient clients.GithubClientService) *GithubAuthManager {
+	return &GithubAuthManager{
+		authenticator: authenticator,
+		githubClient:  githubClient,
+	}
+}
+
+func (m *GithubAuthManager) Login(ctx context.Context) (authenticationmodels.AuthStatus, error) {
+	accessToken, err := m.authenticator.Login(ctx)
+	if err != nil {
+		return authenticationmodels.AuthStatus{}, err
+	}
+
+	return m.describe(accessToken, ctx)
+}
+
+func (m *GithubAuthManager) Status(ctx context.Context) (authenticationmodels.AuthStatus, error) {
+	accessToken, err := m.authenticator.CurrentToken(ctx)
+	if err != nil {
+		return authenticationmodels.AuthStatus{}, err
+	}
+
+	if accessToken.Token == "" {
+		return authenticationmodels.AuthStatus{Method: m.authenticator.Method()}, nil
+	}
+
+	return m.describe(accessToken, ctx)
+}
+
+func (m *GithubAuthManager) Logout() error {
+	return m.authenticator.Logout()
+}
+
+func (m *GithubAuthManager) describe(accessToken authenticationmodels.GithubAccessToken, ctx context.Context) (authenticationmodels.AuthStatus, error) {
+	status := authenticationmodels.AuthStatus{Method: m.authenticator.Method(), LoggedIn: true}
+
+	tokenInfo, err := m.githubClient.GetTokenInfo(accessToken.Token, ctx)
+	if errors.Is(err, clients.ErrTokenUnauthorized) {
+		return status, nil
+	}
+	if err != nil {
+		return authenticationmodels.AuthStatus{}, fmt.Errorf("error checking github token: %w", err)
+	}
+
+	status.Valid = true
+	status.TokenInfo = tokenInfo
+	return status, nil
+}
@@ -2,64 +2,96 @@ package gitubauthenticationservice
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
+	"slices"
 	"time"
 
 	cache "github.com/RobsonDevCode/deepscan/internal/caching"
+	"github.com/RobsonDevCode/deepscan/internal/clients"
 	githubauthenticationclient "github.com/RobsonDevCode/deepscan/internal/clients/githubAuthenticationClient"
 	authenticationmodels "github.com/RobsonDevCode/deepscan/internal/clients/models/githubAuthentication"
+	credentialstore "github.com/RobsonDevCode/deepscan/internal/credentialStore"
 	setupservice "github.com/RobsonDevCode/deepscan/internal/services/setupService"
+	"github.com/fatih/color"
 )
 
 type GithubAuthenticator struct {
 	githubAuthenticationClient githubauthenticationclient.GithubAuthenticationClientService
+	githubClient               clients.GithubClientService
+	credentialStore            credentialstore.CredentialStoreService
 	cache                      *cache.Cache
+	nonInteractive             bool
 }
 
 type GithubAuthenticatorService interface {
 	AuthenticateUser(ctx context.Context) (authenticationmodels.GithubAccessToken, error)
+	AuthenticateOwner(owner string, ctx context.Context) (authenticationmodels.GithubAccessToken, error)
+	// Login always signs in again, replacing whatever was stored
+	Login(ctx context.Context) (authenticationmodels.GithubAccessToken, error)
+	Logout() error
+	// CurrentToken never prompts, an empty token means nobody has logged in
+	CurrentToken(ctx context.Context) (authenticationmodels.GithubAccessToken, error)
+	Method() string
+	// SetNonInteractive stops AuthenticateUser starting the device flow when no one is there to enter the code
+	SetNonInteractive(nonInteractive bool)
 }
 
-const (
-	authenticaionCacheKey = "auth-key"
-	filePath              = "configuration/user_setting.json"
-	tmpFilePath           = "configuration/user_setting_tmp.json"
-)
+const authenticaionCacheKey = "auth-key"
+
+// the device flow asks for repo, a stored token without it cant list private repositories
+const requiredScope = "repo"
+
+// errNoGithubToken ci has no one to enter a device code so it has to bring its own token
+var errNoGithubToken = errors.New("no github token: set DEEPSCAN_GITHUB_TOKEN or run `deepscan auth login`")
 
 func NewGithubAuthenticator(githubauthenticationClient githubauthenticationclient.GithubAuthenticationClientService,
-	cache *cache.Cache) GithubAuthenticator {
+	githubClient clients.GithubClientService, credentialStore credentialstore.CredentialStoreService, cache *cache.Cache) GithubAuthenticator {
 	return GithubAuthenticator{
 		githubAuthenticationClient: githubauthenticationClient,
+		githubClient:               githubClient,
+		credentialStore:            credentialStore,
 		cache:                      cache,
 	}
 }
 
 func (g *GithubAuthenticator) AuthenticateUser(ctx context.Context) (authenticationmodels.GithubAccessToken, error) {
 
-	accessToken, err := getLocalAccessToken()
+	accessToken, err := g.localAccessToken()
 	if err != nil {
 		return authenticationmodels.GithubAccessToken{}, err
 	}
 	if accessToken.Token != "" {
-		return accessToken, nil
-	}
+		if g.isUsable(accessToken, ctx) {
+			return accessToken, nil
+		}
 
-	response, err := g.cache.GetOrCreate(authenticaionCacheKey, func(entry *cache.CacheEntry) (interface{}, error) {
-		deviceCode, err := g.githubAuthenticationClient.GetDeviceCode(ctx)
-		if err != nil {
+		//theres no one to sign in again when the token came from the environment
+		if variable, overridden := g.credentialStore.Overridden(credentialstore.Github); overridden {
+			return authenticationmodels.GithubAccessToken{}, fmt.Errorf("github rejected the token in %s, it was revoked or is missing the %s scope", variable, requiredScope)
+		}
+
+		if g.nonInteractive {
+			return authenticationmodels.GithubAccessToken{}, fmt.Errorf("stored github token was revoked or is missing the %s scope, %w", requiredScope, errNoGithubToken)
+		}
+
+		fmt.Print(color.YellowString("\nStored github token was revoked or is missing the %s scope, signing in again", requiredScope))
+		if err := g.setLocalAccessToken(nil); err != nil {
 			return authenticationmodels.GithubAccessToken{}, err
 		}
+	}
+
+	if g.nonInteractive {
+		return authenticationmodels.GithubAccessToken{}, errNoGithubToken
+	}
 
-		displayUserInstructions(deviceCode)
-		result, err := g.githubAuthenticationClient.GetAccessToken(deviceCode, ctx)
+	response, err := g.cache.GetOrCreate(authenticaionCacheKey, func(entry *cache.CacheEntry) (interface{}, error) {
+		result, expiresIn, err := g.deviceFlow(ctx)
 		if err != nil {
 			return authenticationmodels.GithubAccessToken{}, err
 		}
 
-		fmt.Printf("\n Response: %v", result)
-		entry.Expiration = time.Now().Add(time.Duration(deviceCode.ExpriesIn) * time.Second)
+		entry.Expiration = time.Now().Add(expiresIn)
 		return result, nil
 	})
 	if err != nil {
@@ -71,16 +103,105 @@ func (g *GithubAuthenticator) AuthenticateUser(ctx context.Context) (authenticat
 		return authenticationmodels.GithubAccessToken{}, fmt.Errorf("error authenticating user, unable to read respone type: %w", err)
 	}
 
-	fmt.Printf("\nAccess Token: %s", accessToken.Token)
-
-	if err := setLocalAccessToken(accessToken); err != nil {
-		fmt.Print("error saving access token locally: %w", err) //log but dont fail
+	if err := g.setLocalAccessToken(&accessToken); err != nil {
+		fmt.Printf("error saving access token locally: %s", err.Error()) //log but dont fail
 		return accessToken, nil
 	}
 
 	return accessToken, nil
 }
 
+func (g *GithubAuthenticator) Login(ctx context.Context) (authenticationmodels.GithubAccessToken, error) {
+	accessToken, _, err := g.deviceFlow(ctx)
+	if err != nil {
+		return authenticationmodels.GithubAccessToken{}, fmt.Errorf("error authenticating user: %w", err)
+	}
+
+	if err := g.setLocalAccessToken(&accessToken); err != nil {
+		return authenticationmodels.GithubAccessToken{}, fmt.Errorf("error saving access token locally: %w", err)
+	}
+
+	if variable, overridden := g.credentialStore.Overridden(credentialstore.Github); overridden {
+		fmt.Print(color.YellowString("\n%s is set so it will be used instead of the token you just logged in with", variable))
+		return g.localAccessToken()
+	}
+
+	return accessToken, nil
+}
+
+// Logout only forgets the token, revoking it needs the oauth apps secret so that happens in githubs settings
+func (g *GithubAuthenticator) Logout() error {
+	if err := g.setLocalAccessToken(nil); err != nil {
+		return err
+	}
+
+	if variable, overridden := g.credentialStore.Overridden(credentialstore.Github); overridden {
+		fmt.Print(color.YellowString("\n%s is still set so deepscan will keep using it", variable))
+	}
+
+	return nil
+}
+
+func (g *GithubAuthenticator) CurrentToken(ctx context.Context) (authenticationmodels.GithubAccessToken, error) {
+	return g.localAccessToken()
+}
+
+func (g *GithubAuthenticator) Method() string {
+	if variable, overridden := g.credentialStore.Overridden(credentialstore.Github); overridden {
+		return "token in " + variable
+	}
+
+	return "device flow"
+}
+
+func (g *GithubAuthenticator) SetNonInteractive(nonInteractive bool) {
+	g.nonInteractive = nonInteractive
+}
+
+func (g *GithubAuthenticator) deviceFlow(ctx context.Context) (authenticationmodels.GithubAccessToken, time.Duration, error) {
+	deviceCode, err := g.githubAuthenticationClient.GetDeviceCode(ctx)
+	if err != nil {
+		return authenticationmodels.GithubAccessToken{}, 0, err
+	}
+
+	displayUserInstructions(deviceCode)
+	result, err := g.githubAuthenticationClient.GetAccessToken(deviceCode, ctx)
+	if err != nil {
+		return authenticationmodels.GithubAccessToken{}, 0, err
+	}
+
+	return result, time.Duration(deviceCode.ExpriesIn) * time.Second, nil
+}
+
+// isUsable checks a stored token once a run, anything but github rejecting the token keeps it so an outage doesnt force a sign in
+func (g *GithubAuthenticator) isUsable(accessToken authenticationmodels.GithubAccessToken, ctx context.Context) bool {
+	response, err := g.cache.GetOrCreate("auth-check-"+accessToken.Token, func(entry *cache.CacheEntry) (interface{}, error) {
+		entry.Expiration = time.Now().Add(10 * time.Minute)
+
+		tokenInfo, err := g.githubClient.GetTokenInfo(accessToken.Token, ctx)
+		if errors.Is(err, clients.ErrTokenUnauthorized) {
+			return false, nil
+		}
+		if err != nil {
+			fmt.Printf("\nerror checking github token, carrying on with it: %s", err.Error()) //log but dont fail
+			return true, nil
+		}
+
+		return len(tokenInfo.Scopes) == 0 || slices.Contains(tokenInfo.Scopes, requiredScope), nil
+	})
+	if err != nil {
+		return true
+	}
+
+	usable, ok := response.(bool)
+	return !ok || usable
+}
+
+// AuthenticateOwner a device flow token is the users so it works for every owner they can see
+func (g *GithubAuthenticator) AuthenticateOwner(owner string, ctx context.Context) (authenticationmodels.GithubAccessToken, error) {
+	return g.AuthenticateUser(ctx)
+}
+
 func displayUserInstructions(deviceResp authenticationmodels.DeviceResposnse) {
 	fmt.Printf("\n╭─────────────────────────────────────────╮\n")
 	fmt.Printf("│          GitHub Authentication          │\n")
@@ -89,34 +210,47 @@ func displayUserInstructions(deviceResp authenticationmodels.DeviceResposnse) {
 	fmt.Printf("2. Enter code: %s\n", deviceResp.UserCode)
 }
 
-func getLocalAccessToken() (authenticationmodels.GithubAccessToken, error) {
-	userSettings, err := setupservice.GetUserSettings()
+// localAccessToken the token belongs to the github user not a profile so every github profile shares it
+func (g *GithubAuthenticator) localAccessToken() (authenticationmodels.GithubAccessToken, error) {
+	token, err := g.credentialStore.Get(credentialstore.Github)
+	if err != nil {
+		return authenticationmodels.GithubAccessToken{}, fmt.Errorf("error reading stored github token: %w", err)
+	}
+	if token != "" {
+		return authenticationmodels.GithubAccessToken{Token: token}, nil
+	}
+
+	// older versions kept the token in plain text in the user settings, move it into the credential store
+	legacyToken, err := setupservice.GetAccessToken()
 	if err != nil {
 		return authenticationmodels.GithubAccessToken{}, fmt.Errorf("error getting user setting: %w", err)
 	}
+	if legacyToken == nil {
+		return authenticationmodels.GithubAccessToken{}, nil //no error but no access token so user hasnt authenticated before
+	}
 
-	if userSettings.AccessToken != nil {
-		return *userSettings.AccessToken, nil
+	if err := g.setLocalAccessToken(legacyToken); err != nil {
+		return authenticationmodels.GithubAccessToken{}, err
+	}
+	if err := setupservice.RemoveAccessToken(); err != nil {
+		return authenticationmodels.GithubAccessToken{}, fmt.Errorf("error removing plain text github token from user settings: %w", err)
 	}
 
-	return authenticationmodels.GithubAccessToken{}, nil //no error but no access token so user hasnt authenticated before
+	return *legacyToken, nil
 }
 
-func setLocalAccessToken(accessToken authenticationmodels.GithubAccessToken) error {
-	userSettings, err := setupservice.GetUserSettings()
-	if err != nil {
-		return fmt.Errorf("error getting user settings while creating local access token: %w", err)
+// setLocalAccessToken nil removes the stored token
+func (g *GithubAuthenticator) setLocalAccessToken(accessToken *authenticationmodels.GithubAccessToken) error {
+	if accessToken == nil {
+		if err := g.credentialStore.Delete(credentialstore.Github); err != nil {
+			return fmt.Errorf("error removing stored github token: %w", err)
+		}
+		return nil
 	}
 
-	userSettings.AccessToken = &accessToken
-	updatedJson, _ := json.Marshal(userSettings)
-
-	if err := os.WriteFile(tmpFilePath, updatedJson, 0644); err != nil {
-		return err
+	if err := g.credentialStore.Set(credentialstore.Github, accessToken.Token); err != nil {
+		return fmt.Errorf("error storing github token: %w", err)
 	}
 
-	os.Remove(filePath)
-	os.Rename(tmpFilePath, filePath)
-
 	return nil
 }
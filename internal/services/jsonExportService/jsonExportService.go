@@ -0,0 +1,104 @@
+package jsonexportservice
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/RobsonDevCode/deepscan/internal/clients/models"
+	exportformats "github.com/RobsonDevCode/deepscan/internal/constants/exportFormats"
+	"github.com/RobsonDevCode/deepscan/internal/extensions"
+)
+
+// exportedFinding one row per vulnerable package like the excel export, so ci can read either
+type exportedFinding struct {
+	ServiceName         string   `json:"service"`
+	ProjectName         string   `json:"project"`
+	Package             string   `json:"package"`
+	CurrentVersion      string   `json:"current_version"`
+	AdvisoryId          string   `json:"advisory_id"`
+	Type                string   `json:"type"`
+	CveId               string   `json:"cve_id,omitempty"`
+	Summary             string   `json:"summary"`
+	Severity            string   `json:"severity"`
+	CvssScore           *float64 `json:"cvss_score,omitempty"`
+	CvssVector          string   `json:"cvss_vector,omitempty"`
+	Cwes                []string `json:"cwes,omitempty"`
+	RiskScore           int      `json:"risk_score"`
+	Epss                float64  `json:"epss"`
+	KnownExploited      bool     `json:"known_exploited"`
+	Direct              bool     `json:"direct"`
+	FirstPatchedVersion string   `json:"first_patched_version,omitempty"`
+	PublishedAt         string   `json:"published_at,omitempty"`
+	GithubReviewedAt    string   `json:"github_reviewed_at,omitempty"`
+	AdvisoryDatabase    string   `json:"advisory_database"`
+	Url                 string   `json:"url,omitempty"`
+	CommitSha           string   `json:"commit,omitempty"`
+	Profile             string   `json:"profile,omitempty"`
+	Provider            string   `json:"provider,omitempty"`
+}
+
+func ExportPackages(packages []models.ScannedPackage, isFullScan bool, outDirectory string) error {
+	if err := os.MkdirAll(outDirectory, 0755); err != nil {
+		return fmt.Errorf("error creating directory %s, %w", outDirectory, err)
+	}
+
+	findings := []exportedFinding{} //an empty array rather than null when nothing was found
+	for _, pkg := range packages {
+		var cvssScore *float64
+		var cvssVector string
+		if pkg.Cvss != nil {
+			cvssScore = &pkg.Cvss.Score
+			cvssVector = pkg.Cvss.VectorString
+		}
+
+		var cwes []string
+		for _, cwe := range pkg.Cwes {
+			cwes = append(cwes, cwe.CweId)
+		}
+
+		for _, vuln := range pkg.Vulnerabilities {
+			findings = append(findings, exportedFinding{
+				ServiceName:         pkg.ServiceName,
+				ProjectName:         pkg.ProjectName,
+				Package:             vuln.Package.Name,
+				CurrentVersion:      vuln.CurrentVersion,
+				AdvisoryId:          extensions.AdvisoryId(pkg),
+				Type:                pkg.Type,
+				CveId:               pkg.CveId,
+				Summary:             pkg.Summary,
+				Severity:            pkg.Severity,
+				CvssScore:           cvssScore,
+				CvssVector:          cvssVector,
+				Cwes:                cwes,
+				RiskScore:           pkg.RiskScore,
+				Epss:                pkg.Epss,
+				KnownExploited:      pkg.KnownExploited,
+				Direct:              vuln.Direct,
+				FirstPatchedVersion: vuln.FirstPatchedVersion,
+				PublishedAt:         extensions.FormatDate(pkg.PublishedAt),
+				GithubReviewedAt:    extensions.FormatDate(pkg.GithubReviewedAt),
+				AdvisoryDatabase:    pkg.AdvisoryDatabase,
+				Url:                 pkg.HtmlUrl,
+				CommitSha:           pkg.CommitSha,
+				Profile:             pkg.Profile,
+				Provider:            pkg.Provider,
+			})
+		}
+	}
+
+	data, err := json.MarshalIndent(findings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling findings, %w", err)
+	}
+
+	fullPath := filepath.Join(outDirectory, extensions.ExportFileName(packages, isFullScan, exportformats.Json))
+	if err := os.WriteFile(fullPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to save json to %s, %w", fullPath, err)
+	}
+
+	fmt.Printf("Your file has been saved to: %s", fullPath)
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package packagereaderservice
 
 import (
+	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"io/fs"
@@ -9,6 +10,7 @@ import (
 	"strings"
 
 	projecttypessupported "github.com/RobsonDevCode/deepscan/internal/constants/projectTypesSupported"
+	scannermapper "github.com/RobsonDevCode/deepscan/internal/scanner/mapping"
 	scannermodels "github.com/RobsonDevCode/deepscan/internal/scanner/models"
 	npmmodels "github.com/RobsonDevCode/deepscan/internal/thirdPartyCommands/models/npm"
 	npmcommands "github.com/RobsonDevCode/deepscan/internal/thirdPartyCommands/npmCommands"
@@ -18,6 +20,8 @@ import (
 type PackageReaderService interface {
 	ReadCsProject(path *string, ctx context.Context) (scannermodels.CsProject, error)
 	ReadFrontEndProject(path *string, ctx context.Context) (npmmodels.NpmPackageResponse, error)
+	ParseCsProject(content []byte, serviceName string, name string) (scannermodels.CsProject, error)
+	ParsePackageLock(content []byte) (npmmodels.NpmPackageResponse, error)
 	GetProjectType(root string, ctx context.Context) (*string, *string, error)
 }
 
@@ -75,6 +79,34 @@ func (r *PackageReader) ReadCsProject(path *string, ctx context.Context) (scanne
 	return project, nil
 }
 
+// ParseCsProject is for csproj files we only have the content of, remote scans never write them to disk
+func (r *PackageReader) ParseCsProject(content []byte, serviceName string, name string) (scannermodels.CsProject, error) {
+	var project scannermodels.CsProject
+	if err := xml.Unmarshal(content, &project); err != nil {
+		return scannermodels.CsProject{}, fmt.Errorf("error unmarshalling xml file %s error: %w", name, err)
+	}
+
+	project.Name = name
+	project.ServiceName = serviceName
+
+	return project, nil
+}
+
+// ParsePackageLock reads the lockfile ourselves rather than through npm ls, so theres no need for npm or a checkout
+func (r *PackageReader) ParsePackageLock(content []byte) (npmmodels.NpmPackageResponse, error) {
+	var lock npmmodels.PackageLock
+	if err := json.Unmarshal(content, &lock); err != nil {
+		return npmmodels.NpmPackageResponse{}, fmt.Errorf("error unmarshalling package-lock.json: %w", err)
+	}
+
+	response := scannermapper.MapPackageLock(lock)
+	if len(response.NpmPackage) == 0 {
+		return npmmodels.NpmPackageResponse{}, fmt.Errorf("\n error processing json packages are nil")
+	}
+
+	return response, nil
+}
+
 func (r *PackageReader) ReadFrontEndProject(path *string, ctx context.Context) (npmmodels.NpmPackageResponse, error) {
 
 	response, err := npmcommands.GetPackages(*path, ctx)
@@ -6,8 +6,11 @@ import (
 
 	"github.com/RobsonDevCode/deepscan/internal/configuration"
 	supportedproviders "github.com/RobsonDevCode/deepscan/internal/constants/supportedProviders"
+	azurerepositoryservice "github.com/RobsonDevCode/deepscan/internal/services/azureRepositoryService"
+	bitbucketrepositoryservice "github.com/RobsonDevCode/deepscan/internal/services/bitbucketRepositoryService"
+	gitearepositoryservice "github.com/RobsonDevCode/deepscan/internal/services/giteaRepositoryService"
 	githubrepositoryservice "github.com/RobsonDevCode/deepscan/internal/services/githubRepositoryService"
-	azurecommandExcecutor "github.com/RobsonDevCode/deepscan/internal/thirdPartyCommands/azureCommands"
+	gitlabrepositoryservice "github.com/RobsonDevCode/deepscan/internal/services/gitlabRepositoryService"
 	cmdmodels "github.com/RobsonDevCode/deepscan/internal/thirdPartyCommands/models"
 )
 
@@ -16,26 +19,47 @@ type RepositoryReaderFacade interface {
 }
 
 type RepositoryReaderService struct {
-	azureCmds         azurecommandExcecutor.AzureCommandService
+	azureRepoService  azurerepositoryservice.AzureRepositoryService
 	githubRepoService githubrepositoryservice.GitRepositoryService
+	gitlabRepoService gitlabrepositoryservice.GitlabRepositoryService
+	bitbucketService  bitbucketrepositoryservice.BitbucketRepositoryService
+	giteaRepoService  gitearepositoryservice.GiteaRepositoryService
 }
 
-func NewRepositoryReaderService(azureCmds azurecommandExcecutor.AzureCommandService,
-	githubRepoService githubrepositoryservice.GitRepositoryService) RepositoryReaderService {
+func NewRepositoryReaderService(azureRepoService azurerepositoryservice.AzureRepositoryService,
+	githubRepoService githubrepositoryservice.GitRepositoryService,
+	gitlabRepoService gitlabrepositoryservice.GitlabRepositoryService,
+	bitbucketService bitbucketrepositoryservice.BitbucketRepositoryService,
+	giteaRepoService gitearepositoryservice.GiteaRepositoryService) RepositoryReaderService {
 	return RepositoryReaderService{
-		azureCmds:         azureCmds,
+		azureRepoService:  azureRepoService,
 		githubRepoService: githubRepoService,
+		gitlabRepoService: gitlabRepoService,
+		bitbucketService:  bitbucketService,
+		giteaRepoService:  giteaRepoService,
 	}
 }
 
 func (r *RepositoryReaderService) GetRepos(userSettings configuration.UsersSettings, ctx context.Context) ([]cmdmodels.Repository, error) {
 	switch userSettings.Provider {
 	case supportedproviders.Azure:
-		return r.azureCmds.GetRepos(fmt.Sprintf("%v", userSettings.OrganizationUrl), userSettings.Profile)
+		return r.azureRepoService.GetRepos(userSettings.OrganizationUrl, userSettings.Profile, ctx)
 
 	case supportedproviders.Github:
 		return r.githubRepoService.GetRepos(userSettings.Profile, ctx)
 
+	case supportedproviders.Gitlab:
+		return r.gitlabRepoService.GetRepos(userSettings.OrganizationUrl, userSettings.Profile, ctx)
+
+	case supportedproviders.BitbucketCloud:
+		return r.bitbucketService.GetCloudRepos(userSettings.Profile, ctx)
+
+	case supportedproviders.BitbucketDataCenter:
+		return r.bitbucketService.GetDataCenterRepos(userSettings.OrganizationUrl, userSettings.Profile, ctx)
+
+	case supportedproviders.Gitea, supportedproviders.Forgejo:
+		return r.giteaRepoService.GetRepos(userSettings.OrganizationUrl, userSettings.Profile, ctx)
+
 	default:
 		return nil, fmt.Errorf("non supported provider provided")
 	}
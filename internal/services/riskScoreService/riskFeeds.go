@@ -0,0 +1,87 @@
+package riskscoreservice
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type kevFeed struct {
+	Vulnerabilities []struct {
+		CveId string `json:"cveID"`
+	} `json:"vulnerabilities"`
+}
+
+// loadEpss reads the csv published by FIRST, the first line is a #model_version comment before the header
+func loadEpss(path string) (map[string]float64, error) {
+	result := make(map[string]float64)
+	if path == "" {
+		return result, fmt.Errorf("no epss file configured")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return result, fmt.Errorf("no epss scores found at %s", path)
+		}
+		return result, fmt.Errorf("error opening epss scores %s: %w", path, err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return result, fmt.Errorf("error reading epss scores %s: %w", path, err)
+		}
+
+		if len(record) < 2 || !strings.HasPrefix(strings.ToUpper(record[0]), "CVE-") {
+			continue // header row
+		}
+
+		probability, err := strconv.ParseFloat(strings.TrimSpace(record[1]), 64)
+		if err != nil {
+			continue
+		}
+		result[strings.ToUpper(record[0])] = probability
+	}
+
+	return result, nil
+}
+
+func loadKev(path string) (map[string]bool, error) {
+	result := make(map[string]bool)
+	if path == "" {
+		return result, fmt.Errorf("no kev file configured")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return result, fmt.Errorf("no known exploited vulnerabilities found at %s", path)
+		}
+		return result, fmt.Errorf("error reading known exploited vulnerabilities %s: %w", path, err)
+	}
+
+	var feed kevFeed
+	if err := json.Unmarshal(data, &feed); err != nil {
+		return result, fmt.Errorf("error unmarshalling known exploited vulnerabilities %s: %w", path, err)
+	}
+
+	for _, vulnerability := range feed.Vulnerabilities {
+		result[strings.ToUpper(vulnerability.CveId)] = true
+	}
+
+	return result, nil
+}
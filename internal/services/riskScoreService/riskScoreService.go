@@ -0,0 +1,160 @@
+package riskscoreservice
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/RobsonDevCode/deepscan/internal/clients/models"
+	"github.com/RobsonDevCode/deepscan/internal/configuration"
+	riskscoreconstants "github.com/RobsonDevCode/deepscan/internal/scanner/constants/riskScore"
+	scannermodels "github.com/RobsonDevCode/deepscan/internal/scanner/models"
+)
+
+// transitive packages are harder to reach and harder to upgrade so they count for less
+const transitiveDepthFactor = 0.5
+
+var defaultWeights = configuration.RiskScoreWeights{
+	Cvss:        0.4,
+	Epss:        0.25,
+	Kev:         0.2,
+	Depth:       0.1,
+	Criticality: 0.05,
+}
+
+// used when an advisory has no cvss vector, midpoints of the severity bands
+var severityScores = map[string]float64{
+	"critical": 9.5,
+	"high":     8,
+	"medium":   5.5,
+	"low":      2,
+}
+
+var criticalityFactors = map[string]float64{
+	riskscoreconstants.Critical: 1,
+	riskscoreconstants.High:     0.75,
+	riskscoreconstants.Medium:   0.5,
+	riskscoreconstants.Low:      0.25,
+}
+
+type RiskScoreService interface {
+	Score(projectFile scannermodels.Project, packages []models.ScannedPackage)
+}
+
+type RiskScorer struct {
+	settings configuration.RiskScoreSettings
+	weights  configuration.RiskScoreWeights
+
+	once sync.Once
+	epss map[string]float64
+	kev  map[string]bool
+}
+
+func NewRiskScorer(config *configuration.Config) *RiskScorer {
+	weights := config.RiskScoreSettings.Weights
+	if weights.Cvss+weights.Epss+weights.Kev+weights.Depth+weights.Criticality <= 0 {
+		weights = defaultWeights
+	}
+
+	return &RiskScorer{
+		settings: config.RiskScoreSettings,
+		weights:  weights,
+	}
+}
+
+// Score sets a 0-100 risk score on each finding, scores arent cached as the feeds and criticality can change between runs
+func (r *RiskScorer) Score(projectFile scannermodels.Project, packages []models.ScannedPackage) {
+	r.once.Do(r.loadFeeds)
+
+	criticality := r.criticality(projectFile.ServiceName)
+	totalWeight := r.weights.Cvss + r.weights.Epss + r.weights.Kev + r.weights.Depth + r.weights.Criticality
+
+	for i := range packages {
+		pkg := &packages[i]
+
+		depth := transitiveDepthFactor
+		for j := range pkg.Vulnerabilities {
+			pkg.Vulnerabilities[j].Direct = projectFile.DirectDependencies[pkg.Vulnerabilities[j].Package.Name]
+			if pkg.Vulnerabilities[j].Direct {
+				depth = 1
+			}
+		}
+
+		pkg.Epss = 0
+		pkg.KnownExploited = false
+		for _, cveId := range cveIds(*pkg) {
+			pkg.Epss = math.Max(pkg.Epss, r.epss[cveId])
+			pkg.KnownExploited = pkg.KnownExploited || r.kev[cveId]
+		}
+
+		var kev float64
+		if pkg.KnownExploited {
+			kev = 1
+		}
+
+		weighted := r.weights.Cvss*baseScore(*pkg)/10 +
+			r.weights.Epss*pkg.Epss +
+			r.weights.Kev*kev +
+			r.weights.Depth*depth +
+			r.weights.Criticality*criticality
+
+		pkg.RiskScore = int(math.Round(weighted / totalWeight * riskscoreconstants.MaxScore))
+	}
+}
+
+func (r *RiskScorer) loadFeeds() {
+	epss, err := loadEpss(r.settings.EpssFile)
+	if err != nil {
+		fmt.Printf("\n%v, scoring without exploit probability", err)
+	}
+
+	kev, err := loadKev(r.settings.KevFile)
+	if err != nil {
+		fmt.Printf("\n%v, scoring without known exploited vulnerabilities", err)
+	}
+
+	r.epss = epss
+	r.kev = kev
+}
+
+func (r *RiskScorer) criticality(serviceName string) float64 {
+	tag := r.settings.DefaultCriticality
+	for repository, repositoryTag := range r.settings.Criticality {
+		if strings.EqualFold(repository, serviceName) {
+			tag = repositoryTag
+			break
+		}
+	}
+
+	factor, ok := criticalityFactors[strings.ToLower(tag)]
+	if !ok {
+		return criticalityFactors[riskscoreconstants.Medium]
+	}
+
+	return factor
+}
+
+func baseScore(pkg models.ScannedPackage) float64 {
+	if pkg.Cvss != nil {
+		return pkg.Cvss.Score
+	}
+
+	return severityScores[pkg.Severity]
+}
+
+// cveIds epss and kev are both keyed by cve so we check every cve the advisory is known by
+func cveIds(pkg models.ScannedPackage) []string {
+	var result []string
+	if pkg.CveId != "" {
+		result = append(result, strings.ToUpper(pkg.CveId))
+	}
+
+	for _, alias := range pkg.Aliases {
+		if strings.HasPrefix(strings.ToUpper(alias), "CVE-") {
+			result = append(result, strings.ToUpper(alias))
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,170 @@
+package scandiffservice
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/RobsonDevCode/deepscan/internal/clients/models"
+	"github.com/RobsonDevCode/deepscan/internal/extensions"
+	scannerService "github.com/RobsonDevCode/deepscan/internal/scanner"
+	scannermodels "github.com/RobsonDevCode/deepscan/internal/scanner/models"
+	githubcommands "github.com/RobsonDevCode/deepscan/internal/thirdPartyCommands/githubCommands"
+	"github.com/fatih/color"
+)
+
+type ScanDiffService interface {
+	Diff(source string, base string, head string, options scannermodels.ScanOptions, ctx context.Context) ([]models.ProjectDiff, error)
+}
+
+// DiffProcessor scans a repository at two refs and compares the findings, source is a local repository or a clone url
+type DiffProcessor struct {
+	scanner scannerService.ScannerService
+	mirror  *githubcommands.RepositoryMirror
+}
+
+func NewDiffProcessor(scanner scannerService.ScannerService, mirror *githubcommands.RepositoryMirror) *DiffProcessor {
+	return &DiffProcessor{
+		scanner: scanner,
+		mirror:  mirror,
+	}
+}
+
+func (d *DiffProcessor) Diff(source string, base string, head string, options scannermodels.ScanOptions, ctx context.Context) ([]models.ProjectDiff, error) {
+	if base == "" || head == "" {
+		return nil, fmt.Errorf("both a base and a head ref are needed to diff")
+	}
+
+	mirror := d.mirror
+	// local repositories are cloned from disk so theres nothing for the mirror to save
+	if info, err := os.Stat(source); err == nil && info.IsDir() {
+		absolutePath, err := filepath.Abs(source)
+		if err != nil {
+			return nil, fmt.Errorf("error finding repository %s: %w", source, err)
+		}
+		source = absolutePath
+		mirror = nil
+	}
+
+	baseProjects, err := d.scanRef(source, base, mirror, options, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	headProjects, err := d.scanRef(source, head, mirror, options, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return diffProjects(baseProjects, headProjects), nil
+}
+
+func (d *DiffProcessor) scanRef(source string, ref string, mirror *githubcommands.RepositoryMirror, options scannermodels.ScanOptions, ctx context.Context) ([]models.ScannerResponse, error) {
+	fmt.Printf("\nScanning %s at %s", source, color.CyanString("%s", ref))
+
+	directory, err := githubcommands.CreateWorkingDirectory()
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(directory)
+
+	sha, err := githubcommands.CloneRepository(source, ref, nil, mirror, directory, ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error cloning %s at %s: %w", source, ref, err)
+	}
+	options.Commits = map[string]string{githubcommands.RepositoryDirectory(source): sha}
+
+	return d.scanner.ScanProject(directory, options, ctx)
+}
+
+// diffProjects matches projects by service and project name and findings by advisory and package,
+// a finding whose version changed but is still vulnerable counts as unchanged
+func diffProjects(baseProjects []models.ScannerResponse, headProjects []models.ScannerResponse) []models.ProjectDiff {
+	diffs := make(map[string]*models.ProjectDiff)
+	baseFindings := make(map[string]map[string]models.ScannedPackage)
+
+	getDiff := func(project models.ScannerResponse) *models.ProjectDiff {
+		key := project.ServiceName + "/" + project.Name
+		diff, exists := diffs[key]
+		if !exists {
+			diff = &models.ProjectDiff{ServiceName: project.ServiceName, Name: project.Name}
+			diffs[key] = diff
+		}
+
+		return diff
+	}
+
+	for _, project := range baseProjects {
+		diff := getDiff(project)
+		diff.BaseCommitSha = project.CommitSha
+
+		key := project.ServiceName + "/" + project.Name
+		if baseFindings[key] == nil {
+			baseFindings[key] = make(map[string]models.ScannedPackage)
+		}
+		for findingKey, finding := range findings(project) {
+			baseFindings[key][findingKey] = finding
+		}
+	}
+
+	for _, project := range headProjects {
+		diff := getDiff(project)
+		diff.HeadCommitSha = project.CommitSha
+
+		key := project.ServiceName + "/" + project.Name
+		for findingKey, finding := range findings(project) {
+			if _, exists := baseFindings[key][findingKey]; exists {
+				diff.Unchanged = append(diff.Unchanged, finding)
+				delete(baseFindings[key], findingKey)
+			} else {
+				diff.Added = append(diff.Added, finding)
+			}
+		}
+	}
+
+	// anything left in base was fixed or went with a removed project
+	for key, remaining := range baseFindings {
+		for _, finding := range remaining {
+			diffs[key].Removed = append(diffs[key].Removed, finding)
+		}
+	}
+
+	var result []models.ProjectDiff
+	for _, diff := range diffs {
+		sortFindings(diff.Added)
+		sortFindings(diff.Removed)
+		sortFindings(diff.Unchanged)
+		result = append(result, *diff)
+	}
+
+	slices.SortFunc(result, func(a, b models.ProjectDiff) int {
+		return cmp.Or(cmp.Compare(a.ServiceName, b.ServiceName), cmp.Compare(a.Name, b.Name))
+	})
+
+	return result
+}
+
+// findings splits packages so each finding is one advisory against one package
+func findings(project models.ScannerResponse) map[string]models.ScannedPackage {
+	result := make(map[string]models.ScannedPackage)
+	for _, pkg := range extensions.FlatternPackages([]models.ScannerResponse{project}) {
+		for _, vulnerability := range pkg.Vulnerabilities {
+			finding := pkg
+			finding.Vulnerabilities = []models.Vulnerability{vulnerability}
+			result[extensions.AdvisoryId(pkg)+"|"+vulnerability.Package.Name] = finding
+		}
+	}
+
+	return result
+}
+
+func sortFindings(findings []models.ScannedPackage) {
+	slices.SortFunc(findings, func(a, b models.ScannedPackage) int {
+		return cmp.Or(extensions.SeverityRank(b)-extensions.SeverityRank(a),
+			cmp.Compare(a.Vulnerabilities[0].Package.Name, b.Vulnerabilities[0].Package.Name),
+			cmp.Compare(extensions.AdvisoryId(a), extensions.AdvisoryId(b)))
+	})
+}
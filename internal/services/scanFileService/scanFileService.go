@@ -7,12 +7,13 @@ import (
 
 	"github.com/RobsonDevCode/deepscan/internal/clients/models"
 	scannerService "github.com/RobsonDevCode/deepscan/internal/scanner"
+	scannermodels "github.com/RobsonDevCode/deepscan/internal/scanner/models"
 	packagereaderservice "github.com/RobsonDevCode/deepscan/internal/services/packageReaderService"
 	"github.com/fatih/color"
 )
 
 type ScanFileService interface {
-	ScanProjectFile(filepath string, ctx context.Context) ([]models.ScannerResponse, error)
+	ScanProjectFile(filepath string, options scannermodels.ScanOptions, ctx context.Context) ([]models.ScannerResponse, error)
 }
 
 type FileProcessor struct {
@@ -27,13 +28,13 @@ func NewFileScannerService(scanner scannerService.ScannerService, packageReader
 	}
 }
 
-func (f *FileProcessor) ScanProjectFile(filePath string, ctx context.Context) ([]models.ScannerResponse, error) {
+func (f *FileProcessor) ScanProjectFile(filePath string, options scannermodels.ScanOptions, ctx context.Context) ([]models.ScannerResponse, error) {
 	parts := strings.Split(filePath, "\\")
 	selectedProject := &parts[(len(parts) - 1)]
 
 	fmt.Printf("Selected Project: %s \n", color.CyanString("%s", *selectedProject))
 
-	scannedProject, err := f.scanner.ScanProject(filePath, ctx)
+	scannedProject, err := f.scanner.ScanProject(filePath, options, ctx)
 	if err != nil {
 		return nil, err
 	}
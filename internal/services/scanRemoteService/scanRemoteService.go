@@ -0,0 +1,398 @@
+package scanremoteservice
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/RobsonDevCode/deepscan/internal/clients"
+	azuredevopsclient "github.com/RobsonDevCode/deepscan/internal/clients/azureDevopsClient"
+	gitlabclient "github.com/RobsonDevCode/deepscan/internal/clients/gitlabClient"
+	"github.com/RobsonDevCode/deepscan/internal/clients/models"
+	"github.com/RobsonDevCode/deepscan/internal/configuration"
+	advisorysources "github.com/RobsonDevCode/deepscan/internal/constants/advisorySources"
+	projecttypessupported "github.com/RobsonDevCode/deepscan/internal/constants/projectTypesSupported"
+	supportedproviders "github.com/RobsonDevCode/deepscan/internal/constants/supportedProviders"
+	memoryfs "github.com/RobsonDevCode/deepscan/internal/memoryFs"
+	repositoryselection "github.com/RobsonDevCode/deepscan/internal/repositorySelection"
+	scannerService "github.com/RobsonDevCode/deepscan/internal/scanner"
+	scannermapper "github.com/RobsonDevCode/deepscan/internal/scanner/mapping"
+	scannermodels "github.com/RobsonDevCode/deepscan/internal/scanner/models"
+	gitubauthenticationservice "github.com/RobsonDevCode/deepscan/internal/services/gitubAuthenticationService"
+	repositoryreaderservice "github.com/RobsonDevCode/deepscan/internal/services/repositoryReaderService"
+	setupservice "github.com/RobsonDevCode/deepscan/internal/services/setupService"
+	githubcommands "github.com/RobsonDevCode/deepscan/internal/thirdPartyCommands/githubCommands"
+	cmdmodels "github.com/RobsonDevCode/deepscan/internal/thirdPartyCommands/models"
+	"golang.org/x/sync/errgroup"
+)
+
+// providers rate limit far sooner than git does so we keep the fan out small
+const maxConcurrentRepositories = 4
+
+type ScanRemoteService interface {
+	FetchAndScanAll(selection configuration.RepositorySelection, options scannermodels.ScanOptions, ctx context.Context) (models.ScanAllResponse, error)
+	FetchAndScanRepositories(repos []cmdmodels.Repository, options scannermodels.ScanOptions, ctx context.Context) (models.ScanAllResponse, error)
+}
+
+// remoteFile is a manifest in the providers tree, id is whatever the provider fetches the content by
+type remoteFile struct {
+	path string
+	id   string
+}
+
+// RemoteProcessor reads manifests straight from the provider api, nothing is cloned so neither git nor ssh keys are needed
+type RemoteProcessor struct {
+	scanner                scannerService.ScannerService
+	repositoryReaderFacade repositoryreaderservice.RepositoryReaderFacade
+	githubClient           clients.GithubClientService
+	githubAuth             gitubauthenticationservice.GithubAuthenticatorService
+	gitlabClient           gitlabclient.GitlabClientService
+	azureDevopsClient      azuredevopsclient.AzureDevopsClientService
+	repositoryRefs         map[string]string
+}
+
+func NewRemoteProcessor(scanner scannerService.ScannerService,
+	repositoryReader repositoryreaderservice.RepositoryReaderFacade,
+	githubClient clients.GithubClientService,
+	githubAuth gitubauthenticationservice.GithubAuthenticatorService,
+	gitlabClient gitlabclient.GitlabClientService,
+	azureDevopsClient azuredevopsclient.AzureDevopsClientService,
+	repositoryRefs map[string]string) *RemoteProcessor {
+	return &RemoteProcessor{
+		scanner:                scanner,
+		repositoryReaderFacade: repositoryReader,
+		githubClient:           githubClient,
+		githubAuth:             githubAuth,
+		gitlabClient:           gitlabClient,
+		azureDevopsClient:      azureDevopsClient,
+		repositoryRefs:         repositoryRefs,
+	}
+}
+
+func (r *RemoteProcessor) FetchAndScanAll(selection configuration.RepositorySelection, options scannermodels.ScanOptions, ctx context.Context) (models.ScanAllResponse, error) {
+	userSettings, err := setupservice.GetUserSettings(options.Profile)
+	if err != nil {
+		return models.ScanAllResponse{}, err
+	}
+
+	repos, err := r.repositoryReaderFacade.GetRepos(*userSettings, ctx)
+	if err != nil {
+		return models.ScanAllResponse{}, err
+	}
+
+	repos, err = repositoryselection.Filter(repos, selection)
+	if err != nil {
+		return models.ScanAllResponse{}, err
+	}
+
+	if len(repos) == 0 {
+		return models.ScanAllResponse{}, fmt.Errorf("no repositories match the selection")
+	}
+
+	return r.FetchAndScanRepositories(repos, options, ctx)
+}
+
+func (r *RemoteProcessor) FetchAndScanRepositories(repos []cmdmodels.Repository, options scannermodels.ScanOptions, ctx context.Context) (models.ScanAllResponse, error) {
+	userSettings, err := setupservice.GetUserSettings(options.Profile)
+	if err != nil {
+		return models.ScanAllResponse{}, err
+	}
+
+	provider := userSettings.Provider
+	owner := userSettings.Profile
+	if options.PackageSource == advisorysources.DependencyGraph {
+		return r.scanDependencyGraphs(provider, owner, repos, options, ctx)
+	}
+
+	if !slices.Contains([]string{supportedproviders.Github, supportedproviders.Azure, supportedproviders.Gitlab}, provider) {
+		return models.ScanAllResponse{}, fmt.Errorf("remote scanning isnt supported for %s, drop --remote to clone instead", provider)
+	}
+
+	var accessToken string
+	if provider == supportedproviders.Github {
+		githubAccessToken, err := r.githubAuth.AuthenticateOwner(owner, ctx)
+		if err != nil {
+			return models.ScanAllResponse{}, err
+		}
+		accessToken = githubAccessToken.Token
+	}
+
+	fsys := memoryfs.New()
+	var mu sync.Mutex
+	commits := make(map[string]string)
+	var failed []models.FailedProjectScan
+	group, gCtx := errgroup.WithContext(ctx)
+	group.SetLimit(maxConcurrentRepositories)
+
+	for _, repo := range repos {
+		group.Go(func() error {
+			sha, manifests, err := r.fetchManifests(provider, repo, accessToken, options, gCtx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return err
+				}
+
+				fmt.Printf("\nfailed to fetch manifests for project: %s", repo.Name)
+				mu.Lock()
+				failed = append(failed, failedRepository(repo, err))
+				mu.Unlock()
+				return nil
+			}
+
+			// the same directory a clone would land in so service names match ssh scans
+			directory := path.Base(repo.Name)
+			mu.Lock()
+			commits[directory] = sha
+			for filePath, content := range manifests {
+				fsys.WriteFile(path.Join(directory, filePath), content)
+			}
+			mu.Unlock()
+
+			fmt.Printf("\nfetched %d manifests from project: %s", len(manifests), repo.Name)
+			return nil
+		})
+	}
+
+	if err := group.Wait(); err != nil {
+		return models.ScanAllResponse{}, fmt.Errorf("error fetching manifests: %w", err)
+	}
+	options.Commits = commits
+
+	//nothing fetched so theres nothing to scan, the failures are the result
+	if len(commits) == 0 {
+		return models.ScanAllResponse{FailedProjects: failed}, nil
+	}
+
+	scannedProjects, err := r.scanner.ScanFileSystem(fsys, options, ctx)
+	if err != nil {
+		return models.ScanAllResponse{}, err
+	}
+
+	scannedProjects.FailedProjects = append(scannedProjects.FailedProjects, failed...)
+	return scannedProjects, nil
+}
+
+// fetchManifests reads every manifest in repo at one commit, the manifests are returned keyed by their path in the repository
+// so nothing from a repository that fails part way through ends up being scanned
+func (r *RemoteProcessor) fetchManifests(provider string, repo cmdmodels.Repository, accessToken string, options scannermodels.ScanOptions, ctx context.Context) (string, map[string][]byte, error) {
+	if repo.ApiUrl == "" {
+		return "", nil, fmt.Errorf("no api url for %s", repo.Name)
+	}
+
+	ref := repositoryselection.Ref(r.repositoryRefs, repo.Name, options.Ref)
+	if githubcommands.IsRefPattern(ref) {
+		return "", nil, fmt.Errorf("ref %s for %s is a pattern, patterns need git to resolve them so drop --remote", ref, repo.Name)
+	}
+
+	sha, err := r.commitSha(provider, repo, ref, accessToken, ctx)
+	if err != nil {
+		return "", nil, err
+	}
+
+	files, err := r.listManifests(provider, repo, sha, accessToken, ctx)
+	if err != nil {
+		return "", nil, fmt.Errorf("error listing manifests for %s: %w", repo.Name, err)
+	}
+
+	manifests := make(map[string][]byte)
+	for _, file := range files {
+		content, err := r.readFile(provider, repo, file, accessToken, ctx)
+		if err != nil {
+			return "", nil, fmt.Errorf("error reading %s from %s: %w", file.path, repo.Name, err)
+		}
+
+		manifests[file.path] = content
+	}
+
+	return sha, manifests, nil
+}
+
+// scanDependencyGraphs skips manifests entirely and scans the packages github resolved for each repository
+func (r *RemoteProcessor) scanDependencyGraphs(provider string, owner string, repos []cmdmodels.Repository, options scannermodels.ScanOptions, ctx context.Context) (models.ScanAllResponse, error) {
+	if provider != supportedproviders.Github {
+		return models.ScanAllResponse{}, fmt.Errorf("the dependency graph is only available for github, %s repositories need to be cloned or scanned with --remote", provider)
+	}
+
+	githubAccessToken, err := r.githubAuth.AuthenticateOwner(owner, ctx)
+	if err != nil {
+		return models.ScanAllResponse{}, err
+	}
+
+	var mu sync.Mutex
+	var projects []scannermodels.Project
+	commits := make(map[string]string)
+	var failed []models.FailedProjectScan
+	group, gCtx := errgroup.WithContext(ctx)
+	group.SetLimit(maxConcurrentRepositories)
+
+	for _, repo := range repos {
+		group.Go(func() error {
+			sha, repoProjects, err := r.readDependencyGraph(repo, githubAccessToken.Token, options, gCtx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return err
+				}
+
+				fmt.Printf("\nfailed to read the dependency graph of project: %s", repo.Name)
+				mu.Lock()
+				failed = append(failed, failedRepository(repo, err))
+				mu.Unlock()
+				return nil
+			}
+
+			fmt.Printf("\nread %d ecosystems from the dependency graph of project: %s", len(repoProjects), repo.Name)
+
+			mu.Lock()
+			projects = append(projects, repoProjects...)
+			commits[path.Base(repo.Name)] = sha
+			mu.Unlock()
+
+			return nil
+		})
+	}
+
+	if err := group.Wait(); err != nil {
+		return models.ScanAllResponse{}, fmt.Errorf("error reading dependency graphs: %w", err)
+	}
+	options.Commits = commits
+
+	//nothing read so theres nothing to scan, the failures are the result
+	if len(commits) == 0 {
+		return models.ScanAllResponse{FailedProjects: failed}, nil
+	}
+
+	scannedProjects, err := r.scanner.ScanResolvedProjects(projects, options, ctx)
+	if err != nil {
+		return models.ScanAllResponse{}, err
+	}
+
+	scannedProjects.FailedProjects = append(scannedProjects.FailedProjects, failed...)
+	return scannedProjects, nil
+}
+
+func (r *RemoteProcessor) readDependencyGraph(repo cmdmodels.Repository, accessToken string, options scannermodels.ScanOptions, ctx context.Context) (string, []scannermodels.Project, error) {
+	if repo.ApiUrl == "" {
+		return "", nil, fmt.Errorf("no api url for %s", repo.Name)
+	}
+
+	// github only keeps the dependency graph for the default branch
+	if ref := repositoryselection.Ref(r.repositoryRefs, repo.Name, options.Ref); ref != "" {
+		return "", nil, fmt.Errorf("the dependency graph only covers the default branch, %s cant be scanned at %s", repo.Name, ref)
+	}
+
+	sha, err := r.githubClient.GetCommitSha(repo.ApiUrl, "", accessToken, ctx)
+	if err != nil {
+		return "", nil, err
+	}
+
+	sbom, err := r.githubClient.GetDependencyGraphSbom(repo.ApiUrl, accessToken, ctx)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return sha, scannermapper.MapSbomToProjects(path.Base(repo.Name), sbom), nil
+}
+
+// failedRepository a repository we couldnt read is reported alongside the results instead of failing every other repository
+func failedRepository(repo cmdmodels.Repository, err error) models.FailedProjectScan {
+	return models.FailedProjectScan{
+		Error:       err,
+		ServiceName: path.Base(repo.Name),
+		ProjectName: repo.Name,
+	}
+}
+
+// commitSha pins the scan to one commit so every file we read comes from the same tree even if the branch moves mid scan
+func (r *RemoteProcessor) commitSha(provider string, repo cmdmodels.Repository, ref string, accessToken string, ctx context.Context) (string, error) {
+	switch provider {
+	case supportedproviders.Github:
+		return r.githubClient.GetCommitSha(repo.ApiUrl, ref, accessToken, ctx)
+
+	case supportedproviders.Gitlab:
+		return r.gitlabClient.GetCommitSha(repo.ApiUrl, ref, ctx)
+
+	case supportedproviders.Azure:
+		return r.azureDevopsClient.GetCommitSha(repo.ApiUrl, ref, ctx)
+
+	default:
+		return "", fmt.Errorf("non supported provider provided")
+	}
+}
+
+func (r *RemoteProcessor) listManifests(provider string, repo cmdmodels.Repository, sha string, accessToken string, ctx context.Context) ([]remoteFile, error) {
+	var files []remoteFile
+
+	switch provider {
+	case supportedproviders.Github:
+		tree, err := r.githubClient.GetTree(repo.ApiUrl, sha, accessToken, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		//log but dont fail, we still scan what we got back
+		if tree.Truncated {
+			fmt.Printf("\n%s is too large for a single tree request, some manifests may be missing", repo.Name)
+		}
+
+		for _, entry := range tree.Tree {
+			if entry.Type == "blob" {
+				files = append(files, remoteFile{path: entry.Path, id: entry.Sha})
+			}
+		}
+
+	case supportedproviders.Gitlab:
+		entries, err := r.gitlabClient.GetRepositoryTree(repo.ApiUrl, sha, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			if entry.Type == "blob" {
+				files = append(files, remoteFile{path: entry.Path, id: entry.Id})
+			}
+		}
+
+	case supportedproviders.Azure:
+		items, err := r.azureDevopsClient.GetItems(repo.ApiUrl, sha, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range items {
+			if !item.IsFolder && item.GitObjectType == "blob" {
+				files = append(files, remoteFile{path: strings.TrimPrefix(item.Path, "/"), id: item.ObjectId})
+			}
+		}
+	}
+
+	return slices.DeleteFunc(files, func(file remoteFile) bool {
+		return !isManifest(file.path)
+	}), nil
+}
+
+func (r *RemoteProcessor) readFile(provider string, repo cmdmodels.Repository, file remoteFile, accessToken string, ctx context.Context) ([]byte, error) {
+	switch provider {
+	case supportedproviders.Github:
+		return r.githubClient.GetBlob(repo.ApiUrl, file.id, accessToken, ctx)
+
+	case supportedproviders.Gitlab:
+		return r.gitlabClient.GetBlob(repo.ApiUrl, file.id, ctx)
+
+	case supportedproviders.Azure:
+		return r.azureDevopsClient.GetBlob(repo.ApiUrl, file.id, ctx)
+
+	default:
+		return nil, fmt.Errorf("non supported provider provided")
+	}
+}
+
+func isManifest(filePath string) bool {
+	name := path.Base(filePath)
+	return slices.ContainsFunc(projecttypessupported.ManifestPatterns, func(pattern string) bool {
+		matched, _ := path.Match(pattern, name)
+		return matched
+	})
+}
@@ -7,42 +7,77 @@ import (
 	"strings"
 
 	"github.com/RobsonDevCode/deepscan/internal/clients/models"
+	"github.com/RobsonDevCode/deepscan/internal/configuration"
+	repositoryselection "github.com/RobsonDevCode/deepscan/internal/repositorySelection"
 	scannerService "github.com/RobsonDevCode/deepscan/internal/scanner"
-	scannerconstants "github.com/RobsonDevCode/deepscan/internal/scanner/constants"
+	scannermodels "github.com/RobsonDevCode/deepscan/internal/scanner/models"
+	gitcredentialservice "github.com/RobsonDevCode/deepscan/internal/services/gitCredentialService"
 	repositoryreaderservice "github.com/RobsonDevCode/deepscan/internal/services/repositoryReaderService"
 	setupservice "github.com/RobsonDevCode/deepscan/internal/services/setupService"
 	githubcommands "github.com/RobsonDevCode/deepscan/internal/thirdPartyCommands/githubCommands"
+	cmdmodels "github.com/RobsonDevCode/deepscan/internal/thirdPartyCommands/models"
 	"github.com/fatih/color"
 )
 
 type ScanSSHService interface {
-	Scan(sshUrl string, ctx context.Context) ([]models.ScannerResponse, error)
-	CloneAndScanAll(ctx context.Context) (models.ScanAllResponse, error)
+	Scan(sshUrl string, options scannermodels.ScanOptions, ctx context.Context) ([]models.ScannerResponse, error)
+	CloneAndScanAll(selection configuration.RepositorySelection, options scannermodels.ScanOptions, ctx context.Context) (models.ScanAllResponse, error)
+	CloneAndScanRepositories(repos []cmdmodels.Repository, options scannermodels.ScanOptions, ctx context.Context) (models.ScanAllResponse, error)
 }
 
+// HttpsProtocol clones with the providers https url and token instead of ssh keys
+const HttpsProtocol = "https"
+
 type SShProcessor struct {
 	scanner                scannerService.ScannerService
 	repositoryReaderFacade repositoryreaderservice.RepositoryReaderFacade
+	mirror                 *githubcommands.RepositoryMirror
+	repositoryRefs         map[string]string
+	gitCredentials         gitcredentialservice.GitCredentialService
+	cloneOverHttps         bool
 }
 
-func NewSshProcessor(scanner scannerService.ScannerService, repositoryReader repositoryreaderservice.RepositoryReaderFacade) *SShProcessor {
+func NewSshProcessor(scanner scannerService.ScannerService, repositoryReader repositoryreaderservice.RepositoryReaderFacade, mirror *githubcommands.RepositoryMirror,
+	repositoryRefs map[string]string,
+	gitCredentials gitcredentialservice.GitCredentialService,
+	cloneProtocol string) *SShProcessor {
 	return &SShProcessor{
 		scanner:                scanner,
 		repositoryReaderFacade: repositoryReader,
+		mirror:                 mirror,
+		repositoryRefs:         repositoryRefs,
+		gitCredentials:         gitCredentials,
+		cloneOverHttps:         cloneProtocol == HttpsProtocol,
 	}
 }
 
-func (s *SShProcessor) Scan(sshUrl string, ctx context.Context) ([]models.ScannerResponse, error) {
+func (s *SShProcessor) Scan(sshUrl string, options scannermodels.ScanOptions, ctx context.Context) ([]models.ScannerResponse, error) {
 	parts := strings.Split(sshUrl, "/")
 	selectedProject := parts[(len(parts) - 1)]
 	fmt.Printf("Selected Project: %s \n", color.CyanString("%s", selectedProject))
 
-	err := githubcommands.CloneRepository(sshUrl, ctx)
+	var credentials *githubcommands.Credentials
+	if isHttpsUrl(sshUrl) {
+		userCredentials, err := s.credentials(options.Profile, ctx)
+		if err != nil {
+			return nil, err
+		}
+		credentials = userCredentials
+	}
+
+	directory, err := githubcommands.CreateWorkingDirectory()
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(directory)
+
+	sha, err := githubcommands.CloneRepository(sshUrl, options.Ref, credentials, s.mirror, directory, ctx)
 	if err != nil {
 		return nil, fmt.Errorf("error cloning %s error: %w", selectedProject, err)
 	}
+	options.Commits = map[string]string{githubcommands.RepositoryDirectory(sshUrl): sha}
 
-	scannedProject, err := s.scanner.ScanProject(scannerconstants.TempDirctory, ctx)
+	scannedProject, err := s.scanner.ScanProject(directory, options, ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -50,8 +85,8 @@ func (s *SShProcessor) Scan(sshUrl string, ctx context.Context) ([]models.Scanne
 	return scannedProject, nil
 }
 
-func (s *SShProcessor) CloneAndScanAll(ctx context.Context) (models.ScanAllResponse, error) {
-	userSettings, err := setupservice.GetUserSettings()
+func (s *SShProcessor) CloneAndScanAll(selection configuration.RepositorySelection, options scannermodels.ScanOptions, ctx context.Context) (models.ScanAllResponse, error) {
+	userSettings, err := setupservice.GetUserSettings(options.Profile)
 	if err != nil {
 		return models.ScanAllResponse{}, err
 	}
@@ -65,26 +100,83 @@ func (s *SShProcessor) CloneAndScanAll(ctx context.Context) (models.ScanAllRespo
 		return models.ScanAllResponse{}, fmt.Errorf("repositories return nil")
 	}
 
+	repos, err = repositoryselection.Filter(repos, selection)
+	if err != nil {
+		return models.ScanAllResponse{}, err
+	}
+
+	if len(repos) == 0 {
+		return models.ScanAllResponse{}, fmt.Errorf("no repositories match the selection")
+	}
+
+	return s.CloneAndScanRepositories(repos, options, ctx)
+}
+
+func (s *SShProcessor) CloneAndScanRepositories(repos []cmdmodels.Repository, options scannermodels.ScanOptions, ctx context.Context) (models.ScanAllResponse, error) {
+	overHttps := s.cloneOverHttps || options.CloneOverHttps
+
 	var sshUrls []string
+	var credentials *githubcommands.Credentials
+	refs := make(map[string]string)
 	for _, repo := range repos {
-		sshUrls = append(sshUrls, repo.SSHUrl)
+		url := repo.SSHUrl
+		if overHttps && repo.HttpsUrl != "" {
+			url = repo.HttpsUrl
+		}
+
+		if isHttpsUrl(url) && credentials == nil {
+			userCredentials, err := s.credentials(options.Profile, ctx)
+			if err != nil {
+				return models.ScanAllResponse{}, err
+			}
+			credentials = userCredentials
+		}
+
+		sshUrls = append(sshUrls, url)
+		if ref := repositoryselection.Ref(s.repositoryRefs, repo.Name, options.Ref); ref != "" {
+			refs[url] = ref
+		}
 	}
 	if len(sshUrls) == 0 {
 		return models.ScanAllResponse{}, fmt.Errorf("error: sshUrls cannot be nil or empty when trying to clone and scan")
 	}
 
-	if err := githubcommands.CloneAll(sshUrls, ctx); err != nil {
-		deleteErr := os.RemoveAll(scannerconstants.TempDirctory)
-		if deleteErr != nil {
-			return models.ScanAllResponse{}, deleteErr
-		}
+	directory, err := githubcommands.CreateWorkingDirectory()
+	if err != nil {
+		return models.ScanAllResponse{}, err
+	}
+	defer os.RemoveAll(directory)
 
+	commits, failedClones, err := githubcommands.CloneAll(sshUrls, refs, credentials, s.mirror, directory, ctx)
+	if err != nil {
 		return models.ScanAllResponse{}, fmt.Errorf("error cloning all repos: %w", err)
 	}
+	options.Commits = commits
+
+	//nothing cloned so theres nothing to scan, the failures are the result
+	if len(commits) == 0 {
+		return models.ScanAllResponse{FailedProjects: failedClones}, nil
+	}
 
-	scannedProjects, err := s.scanner.ScanProjects(ctx)
+	scannedProjects, err := s.scanner.ScanProjects(directory, options, ctx)
 	if err != nil {
 		return models.ScanAllResponse{}, err
 	}
+
+	scannedProjects.FailedProjects = append(scannedProjects.FailedProjects, failedClones...)
 	return scannedProjects, nil
 }
+
+// credentials https clones authenticate with the token for the provider set up in deepscan setup
+func (s *SShProcessor) credentials(profile string, ctx context.Context) (*githubcommands.Credentials, error) {
+	userSettings, err := setupservice.GetUserSettings(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.gitCredentials.GetCredentials(userSettings.Provider, userSettings.Profile, ctx)
+}
+
+func isHttpsUrl(url string) bool {
+	return strings.HasPrefix(url, "https://") || strings.HasPrefix(url, "http://")
+}
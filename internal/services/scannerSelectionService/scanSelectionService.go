@@ -3,76 +3,137 @@ package scannerselectionservice
 import (
 	"context"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/RobsonDevCode/deepscan/internal/clients/models"
 	tablewriterservice "github.com/RobsonDevCode/deepscan/internal/cmdLineWriters/tablewriter"
+	"github.com/RobsonDevCode/deepscan/internal/configuration"
+	advisorysources "github.com/RobsonDevCode/deepscan/internal/constants/advisorySources"
 	"github.com/RobsonDevCode/deepscan/internal/extensions"
+	repositoryselection "github.com/RobsonDevCode/deepscan/internal/repositorySelection"
+	scannermodels "github.com/RobsonDevCode/deepscan/internal/scanner/models"
 	repositoryreaderservice "github.com/RobsonDevCode/deepscan/internal/services/repositoryReaderService"
 	scanfileservice "github.com/RobsonDevCode/deepscan/internal/services/scanFileService"
+	scanremoteservice "github.com/RobsonDevCode/deepscan/internal/services/scanRemoteService"
 	scansshservice "github.com/RobsonDevCode/deepscan/internal/services/scanShhService"
 	setupservice "github.com/RobsonDevCode/deepscan/internal/services/setupService"
+	cmdmodels "github.com/RobsonDevCode/deepscan/internal/thirdPartyCommands/models"
 	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 )
 
 type ScannerSelectionService interface {
 	Scan(cmd *cobra.Command, ctx context.Context) ([]models.ScannedPackage, error)
-	ScanAll(ctx context.Context) ([]models.ScannedPackage, error)
+	ScanAll(cmd *cobra.Command, ctx context.Context) ([]models.ScannedPackage, error)
 }
 
 type ScanSelection struct {
 	sshService             scansshservice.ScanSSHService
+	remoteService          scanremoteservice.ScanRemoteService
 	fileService            scanfileservice.ScanFileService
 	repositoryReaderFacade repositoryreaderservice.RepositoryReaderFacade
 }
 
 func NewScanSelection(sshService scansshservice.ScanSSHService,
+	remoteService scanremoteservice.ScanRemoteService,
 	fileService scanfileservice.ScanFileService,
 	repositoryReader repositoryreaderservice.RepositoryReaderFacade) ScanSelection {
 	return ScanSelection{
 		sshService:             sshService,
+		remoteService:          remoteService,
 		fileService:            fileService,
 		repositoryReaderFacade: repositoryReader,
 	}
 }
 
 const (
-	DirFlag = "dir"
-	SSHFlag = "ssh"
+	DirFlag              = "dir"
+	SSHFlag              = "ssh"
+	NoCacheFlag          = "no-cache"
+	SourceFlag           = "source"
+	OfflineFlag          = "offline"
+	GithubApiFlag        = "github-api"
+	IncludeWithdrawnFlag = "include-withdrawn"
+	SelectionFlag        = "selection"
+	SaveSelectionFlag    = "save-selection"
+	IncludeFlag          = "include"
+	ExcludeFlag          = "exclude"
+	TopicFlag            = "topic"
+	RemoteFlag           = "remote"
+	RefFlag              = "ref"
+	HttpsFlag            = "https"
+	ProfileFlag          = "profile"
+	NonInteractiveFlag   = "non-interactive"
 )
 
 func (s *ScanSelection) Scan(cmd *cobra.Command, ctx context.Context) ([]models.ScannedPackage, error) {
 	filePath, _ := cmd.Flags().GetString(DirFlag)
 	sshUrl, _ := cmd.Flags().GetString(SSHFlag)
+	options := GetScanOptions(cmd)
 
 	var scannedProjects []models.ScannerResponse
 	if filePath != "" {
-		scannerResponse, err := s.fileService.ScanProjectFile(filePath, ctx)
+		scannerResponse, err := s.fileService.ScanProjectFile(filePath, options, ctx)
 		if err != nil {
 			return nil, err
 		}
 
 		scannedProjects = scannerResponse
 	} else if sshUrl != "" {
-		project, err := s.sshService.Scan(sshUrl, ctx)
+		project, err := s.sshService.Scan(sshUrl, options, ctx)
 		if err != nil {
 			return nil, err
 		}
 
 		scannedProjects = project
 	} else {
-		selectedSshUrl, err := s.SelectFromAllProjects(ctx)
+		selection, err := getRepositorySelection(cmd)
 		if err != nil {
 			return nil, err
 		}
 
-		scannerResponse, err := s.sshService.Scan(*selectedSshUrl, ctx)
+		if options.Profile == setupservice.AllProfiles {
+			return nil, fmt.Errorf("--profile '%s' scans every profile so it needs --all", setupservice.AllProfiles)
+		}
+
+		userSettings, err := setupservice.GetUserSettings(options.Profile)
 		if err != nil {
 			return nil, err
 		}
 
-		scannedProjects = scannerResponse
+		selectedRepos, err := s.SelectFromAllProjects(selection, *userSettings, options.NonInteractive, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		var selectedNames []string
+		for _, repo := range selectedRepos {
+			selectedNames = append(selectedNames, repo.Name)
+		}
+		if err := saveSelection(cmd, repositoryselection.FromNames(selectedNames)); err != nil {
+			return nil, err
+		}
+
+		var scanAllResponse models.ScanAllResponse
+		if isRemote(cmd, options) {
+			scanAllResponse, err = s.remoteService.FetchAndScanRepositories(selectedRepos, options, ctx)
+		} else {
+			scanAllResponse, err = s.sshService.CloneAndScanRepositories(selectedRepos, options, ctx)
+		}
+		if err != nil {
+			return nil, err
+		}
+		setProfile(&scanAllResponse, *userSettings)
+
+		tablewriterservice.DisplayInfomationTable(scanAllResponse.SuccessfullyScannedProjects)
+		scannedPackages := extensions.FlatternPackages(scanAllResponse.SuccessfullyScannedProjects)
+		tablewriterservice.DisplayPackagesTable(scannedPackages)
+		tablewriterservice.DisplayFailedScanTable(scanAllResponse.FailedProjects)
+
+		return scannedPackages, nil
 	}
 
 	tablewriterservice.DisplayInfomationTable(scannedProjects)
@@ -82,11 +143,51 @@ func (s *ScanSelection) Scan(cmd *cobra.Command, ctx context.Context) ([]models.
 	return scannedPackages, nil
 }
 
-func (s *ScanSelection) ScanAll(ctx context.Context) ([]models.ScannedPackage, error) {
+func (s *ScanSelection) ScanAll(cmd *cobra.Command, ctx context.Context) ([]models.ScannedPackage, error) {
+	selection, err := getRepositorySelection(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := saveSelection(cmd, selection); err != nil {
+		return nil, err
+	}
+
 	fmt.Print("Starting Scan...\n")
-	scanAllResponse, err := s.sshService.CloneAndScanAll(ctx)
+	options := GetScanOptions(cmd)
+
+	profiles, err := setupservice.GetProfiles(options.Profile)
 	if err != nil {
-		return nil, fmt.Errorf("%s", color.RedString(err.Error()))
+		return nil, err
+	}
+
+	var scanAllResponse models.ScanAllResponse
+	for _, profile := range profiles {
+		if len(profiles) > 1 {
+			fmt.Printf("\nScanning profile %s\n", color.CyanString("%s", extensions.FormatProfile(profile.Name, profile.Provider)))
+		}
+
+		profileOptions := options
+		profileOptions.Profile = profile.Name
+
+		var profileResponse models.ScanAllResponse
+		if isRemote(cmd, profileOptions) {
+			profileResponse, err = s.remoteService.FetchAndScanAll(selection, profileOptions, ctx)
+		} else {
+			profileResponse, err = s.sshService.CloneAndScanAll(selection, profileOptions, ctx)
+		}
+		if err != nil {
+			if len(profiles) == 1 {
+				return nil, fmt.Errorf("%s", color.RedString(err.Error()))
+			}
+
+			//one profile failing shouldnt lose the results from the others
+			profileResponse = models.ScanAllResponse{FailedProjects: []models.FailedProjectScan{{Error: err, ServiceName: profile.Name}}}
+		}
+		setProfile(&profileResponse, profile)
+
+		scanAllResponse.SuccessfullyScannedProjects = append(scanAllResponse.SuccessfullyScannedProjects, profileResponse.SuccessfullyScannedProjects...)
+		scanAllResponse.FailedProjects = append(scanAllResponse.FailedProjects, profileResponse.FailedProjects...)
 	}
 
 	scannedPackages := extensions.FlatternPackages(scanAllResponse.SuccessfullyScannedProjects)
@@ -97,39 +198,160 @@ func (s *ScanSelection) ScanAll(ctx context.Context) ([]models.ScannedPackage, e
 	return scannedPackages, nil
 }
 
-func (s *ScanSelection) SelectFromAllProjects(ctx context.Context) (*string, error) {
-	fmt.Print("Loading projects...")
+// GetScanOptions reads the flags every scanning command shares, commands without a flag get its zero value
+func GetScanOptions(cmd *cobra.Command) scannermodels.ScanOptions {
+	noCache, _ := cmd.Flags().GetBool(NoCacheFlag)
+	source, _ := cmd.Flags().GetString(SourceFlag)
+	if offline, _ := cmd.Flags().GetBool(OfflineFlag); offline {
+		source = advisorysources.Offline
+	}
+
+	githubApi, _ := cmd.Flags().GetString(GithubApiFlag)
+	includeWithdrawn, _ := cmd.Flags().GetBool(IncludeWithdrawnFlag)
+	ref, _ := cmd.Flags().GetString(RefFlag)
+	cloneOverHttps, _ := cmd.Flags().GetBool(HttpsFlag)
+	profile, _ := cmd.Flags().GetString(ProfileFlag)
+
+	// dependency-graph picks where packages come from, anything after it picks the advisory source
+	var packageSource string
+	if packageSourceName, advisorySource, _ := strings.Cut(source, ","); packageSourceName == advisorysources.DependencyGraph {
+		packageSource = advisorysources.DependencyGraph
+		source = advisorySource
+		if source == "" {
+			source = advisorysources.Github
+		}
+	}
+
+	return scannermodels.ScanOptions{
+		UseCache:         !noCache,
+		Source:           source,
+		GithubApi:        githubApi,
+		IncludeWithdrawn: includeWithdrawn,
+		PackageSource:    packageSource,
+		Ref:              ref,
+		CloneOverHttps:   cloneOverHttps,
+		Profile:          profile,
+		NonInteractive:   IsNonInteractive(cmd),
+	}
+}
+
+// IsNonInteractive --non-interactive wins when given, otherwise ci and piped runs are spotted by stdin not being a terminal
+func IsNonInteractive(cmd *cobra.Command) bool {
+	if flag := cmd.Flags().Lookup(NonInteractiveFlag); flag != nil && flag.Changed {
+		nonInteractive, _ := cmd.Flags().GetBool(NonInteractiveFlag)
+		return nonInteractive
+	}
+
+	return !isatty.IsTerminal(os.Stdin.Fd()) && !isatty.IsCygwinTerminal(os.Stdin.Fd())
+}
+
+// isRemote the dependency graph is always read through the api so it never needs a clone
+func isRemote(cmd *cobra.Command, options scannermodels.ScanOptions) bool {
+	remote, _ := cmd.Flags().GetBool(RemoteFlag)
+	return remote || options.PackageSource == advisorysources.DependencyGraph
+}
+
+// getRepositorySelection starts from the saved selection when one is named and adds any rules given on the command line
+func getRepositorySelection(cmd *cobra.Command) (configuration.RepositorySelection, error) {
+	includes, _ := cmd.Flags().GetStringSlice(IncludeFlag)
+	excludes, _ := cmd.Flags().GetStringSlice(ExcludeFlag)
+	topics, _ := cmd.Flags().GetStringSlice(TopicFlag)
+	selection := configuration.RepositorySelection{Include: includes, Exclude: excludes, Topics: topics}
 
-	userSettings, err := setupservice.GetUserSettings()
+	name, _ := cmd.Flags().GetString(SelectionFlag)
+	if name == "" {
+		return selection, nil
+	}
+
+	saved, err := setupservice.GetSelection(name)
 	if err != nil {
-		return nil, err
+		return configuration.RepositorySelection{}, err
+	}
+
+	return repositoryselection.Merge(saved, selection), nil
+}
+
+func saveSelection(cmd *cobra.Command, selection configuration.RepositorySelection) error {
+	name, _ := cmd.Flags().GetString(SaveSelectionFlag)
+	if name == "" {
+		return nil
 	}
 
-	projects, err := s.repositoryReaderFacade.GetRepos(*userSettings, ctx)
+	if repositoryselection.IsEmpty(selection) {
+		return fmt.Errorf("nothing to save as %s, add --include, --exclude or --topic rules", name)
+	}
+
+	if err := setupservice.SaveSelection(name, selection); err != nil {
+		return err
+	}
+
+	fmt.Printf("\nSaved selection %s, reuse it with --selection %s\n", color.CyanString(name), name)
+	return nil
+}
+
+// SelectFromAllProjects prompts for which of the matching projects to scan, without a prompt every match is scanned
+// so a selection is needed to stop a non interactive run scanning everything by accident
+func (s *ScanSelection) SelectFromAllProjects(selection configuration.RepositorySelection, userSettings configuration.UsersSettings,
+	nonInteractive bool, ctx context.Context) ([]cmdmodels.Repository, error) {
+	if nonInteractive && repositoryselection.IsEmpty(selection) {
+		return nil, fmt.Errorf("theres no one to pick projects in non-interactive mode, pass a project with --dir or --ssh, narrow them with --include, --exclude, --topic or --selection, or scan everything with --all")
+	}
+
+	fmt.Print("Loading projects...")
+
+	projects, err := s.repositoryReaderFacade.GetRepos(userSettings, ctx)
 	if err != nil {
 		return nil, fmt.Errorf("error getting current projects, %v", err)
 	}
 
+	projects, err = repositoryselection.Filter(projects, selection)
+	if err != nil {
+		return nil, err
+	}
+
 	if len(projects) == 0 {
 		return nil, fmt.Errorf("no projests found")
 	}
 
+	if nonInteractive {
+		fmt.Printf("\nScanning the %d projects matching the selection\n", len(projects))
+		return projects, nil
+	}
+
 	var options []string
 	for _, project := range projects {
 		options = append(options, project.Name)
 	}
 
-	prompt := &survey.Select{
-		Message: "Select a project to scan:",
+	prompt := &survey.MultiSelect{
+		Message: "Select projects to scan:",
 		Options: options,
 	}
 
-	var selectedIndex int
-	err = survey.AskOne(prompt, &selectedIndex)
+	var selectedIndexes []int
+	err = survey.AskOne(prompt, &selectedIndexes, survey.WithValidator(survey.MinItems(1)))
 	if err != nil {
 		fmt.Print("selection cancelled")
 		return nil, fmt.Errorf("survey error: %w", err)
 	}
 
-	return &projects[selectedIndex].SSHUrl, nil
+	var result []cmdmodels.Repository
+	for _, index := range selectedIndexes {
+		result = append(result, projects[index])
+	}
+
+	return result, nil
+}
+
+// setProfile stamps results with the profile they came from so tables and exports can tell profiles apart
+func setProfile(scanAllResponse *models.ScanAllResponse, userSettings configuration.UsersSettings) {
+	for i := range scanAllResponse.SuccessfullyScannedProjects {
+		scanAllResponse.SuccessfullyScannedProjects[i].Profile = userSettings.Name
+		scanAllResponse.SuccessfullyScannedProjects[i].Provider = userSettings.Provider
+	}
+
+	for i := range scanAllResponse.FailedProjects {
+		scanAllResponse.FailedProjects[i].Profile = userSettings.Name
+		scanAllResponse.FailedProjects[i].Provider = userSettings.Provider
+	}
 }
@@ -2,21 +2,43 @@ package setupservice
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
 	"net/url"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 
+	authenticationmodels "github.com/RobsonDevCode/deepscan/internal/clients/models/githubAuthentication"
 	"github.com/RobsonDevCode/deepscan/internal/configuration"
 	supportedproviders "github.com/RobsonDevCode/deepscan/internal/constants/supportedProviders"
 )
 
-const filePath = "configuration/user_setting.json"
+const (
+	settingsFolder = "deepscan"
+	settingsFile   = "user_setting.json"
+	// older versions wrote the settings next to wherever deepscan was run from
+	legacyFilePath = "configuration/user_setting.json"
+)
+
+// DefaultProfileName is used when setup isnt given a name, single profile files from older versions are read in under it too
+const DefaultProfileName = "default"
+
+// AllProfiles scans every profile in one run
+const AllProfiles = "*"
 
-func CreateSetupFile(orgUrl string, provider string, profile string) error {
+var errNotSetUp = errors.New("error, validating set up please insure the set up command has been ran")
+
+// CreateSetupFile adds the profile under name, setting up an existing name again replaces it
+func CreateSetupFile(name string, orgUrl string, provider string, profile string) error {
+	if name == "" {
+		name = DefaultProfileName
+	}
 
-	if _, err := os.Stat(filePath); os.IsExist(err) {
-		return fmt.Errorf("user settings already exist")
+	if name == AllProfiles {
+		return fmt.Errorf("%s is reserved for scanning every profile, pick another name", AllProfiles)
 	}
 
 	var userSettings configuration.UsersSettings
@@ -26,46 +48,311 @@ func CreateSetupFile(orgUrl string, provider string, profile string) error {
 			Profile:         profile,
 			Provider:        supportedproviders.Github,
 		}
+	} else if strings.ToLower(provider) == supportedproviders.Gitlab {
+		if orgUrl == "" {
+			orgUrl = supportedproviders.GitlabUrl
+		}
+
+		parsedUrl, err := url.Parse(orgUrl)
+		if err != nil || parsedUrl.Host == "" {
+			return fmt.Errorf("\ngitlab url seems to be in an incorrect format: %s", orgUrl)
+		}
+
+		userSettings = configuration.UsersSettings{
+			OrganizationUrl: fmt.Sprintf("%s://%s/", parsedUrl.Scheme, parsedUrl.Host),
+			Profile:         strings.Trim(profile, "/"),
+			Provider:        supportedproviders.Gitlab,
+		}
+	} else if strings.ToLower(provider) == supportedproviders.BitbucketCloud {
+		userSettings = configuration.UsersSettings{
+			OrganizationUrl: supportedproviders.BitbucketCloudUrl,
+			Profile:         profile,
+			Provider:        supportedproviders.BitbucketCloud,
+		}
+	} else if strings.ToLower(provider) == supportedproviders.BitbucketDataCenter {
+		parsedUrl, err := url.Parse(orgUrl)
+		if err != nil || parsedUrl.Host == "" {
+			return fmt.Errorf("\nbitbucket url seems to be in an incorrect format: %s", orgUrl)
+		}
+
+		userSettings = configuration.UsersSettings{
+			OrganizationUrl: strings.TrimSuffix(parsedUrl.String(), "/") + "/",
+			Profile:         strings.ToUpper(profile), // project keys are upper case
+			Provider:        supportedproviders.BitbucketDataCenter,
+		}
+	} else if strings.ToLower(provider) == supportedproviders.Gitea || strings.ToLower(provider) == supportedproviders.Forgejo {
+		parsedUrl, err := url.Parse(orgUrl)
+		if err != nil || parsedUrl.Host == "" {
+			return fmt.Errorf("\n%s url seems to be in an incorrect format: %s", provider, orgUrl)
+		}
+
+		userSettings = configuration.UsersSettings{
+			OrganizationUrl: strings.TrimSuffix(parsedUrl.String(), "/") + "/",
+			Profile:         profile,
+			Provider:        strings.ToLower(provider),
+		}
 	} else if strings.ToLower(provider) == supportedproviders.Azure {
 		parsedUrl, err := url.Parse(orgUrl)
-		if err != nil {
-			return fmt.Errorf("\nurl seems to be in an incorrect format: %w", err)
+		if err != nil || parsedUrl.Host == "" {
+			return fmt.Errorf("\nazure url seems to be in an incorrect format: %s", orgUrl)
 		}
 
 		userSettings = configuration.UsersSettings{
-			OrganizationUrl: fmt.Sprintf("%v", *parsedUrl),
+			OrganizationUrl: strings.TrimSuffix(parsedUrl.String(), "/") + "/",
 			Profile:         profile,
 			Provider:        supportedproviders.Azure,
 		}
 	}
 
-	jsonData, err := json.Marshal(userSettings)
+	if userSettings.Provider == "" {
+		return fmt.Errorf("non supported provider provided")
+	}
+
+	userProfiles, err := GetUserProfiles()
+	if err != nil && !errors.Is(err, os.ErrNotExist) && !errors.Is(err, errNotSetUp) {
+		return err
+	}
+	if userProfiles == nil {
+		userProfiles = &configuration.UserProfiles{}
+	}
+
+	if userProfiles.Profiles == nil {
+		userProfiles.Profiles = make(map[string]configuration.UsersSettings)
+	}
+	userProfiles.Profiles[name] = userSettings
+
+	if userProfiles.DefaultProfile == "" {
+		userProfiles.DefaultProfile = name
+	}
+
+	return SaveUserProfiles(userProfiles)
+}
+
+// GetUserSettings reads the named profile, an empty name is the default profile
+func GetUserSettings(name string) (*configuration.UsersSettings, error) {
+	userProfiles, err := GetUserProfiles()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(userProfiles.Profiles) == 0 {
+		return nil, errNotSetUp
+	}
+
+	if name == "" {
+		name = userProfiles.DefaultProfile
+	}
+
+	userSettings, ok := userProfiles.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("no profile called %s, set it up with 'deepscan setup --name %s'", name, name)
+	}
+
+	if userSettings.Profile == "" {
+		return nil, errNotSetUp
+	}
+
+	userSettings.Name = name
+	return &userSettings, nil
+}
+
+// GetProfiles resolves --profile, * is every profile in name order and anything else is a single profile
+func GetProfiles(name string) ([]configuration.UsersSettings, error) {
+	if name != AllProfiles {
+		userSettings, err := GetUserSettings(name)
+		if err != nil {
+			return nil, err
+		}
+
+		return []configuration.UsersSettings{*userSettings}, nil
+	}
+
+	userProfiles, err := GetUserProfiles()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(userProfiles.Profiles) == 0 {
+		return nil, errNotSetUp
+	}
+
+	names := slices.Sorted(maps.Keys(userProfiles.Profiles))
+	var result []configuration.UsersSettings
+	for _, profileName := range names {
+		userSettings, err := GetUserSettings(profileName)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *userSettings)
+	}
+
+	return result, nil
+}
+
+// settingsPath keeps the settings in the users config directory alongside the credential store so they dont depend on the working directory
+func settingsPath() (string, error) {
+	userConfigDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("error finding user config directory: %w", err)
+	}
+
+	return filepath.Join(userConfigDir, settingsFolder, settingsFile), nil
+}
+
+func GetUserProfiles() (*configuration.UserProfiles, error) {
+	path, err := settingsPath()
+	if err != nil {
+		return nil, err
+	}
+
+	jsonData, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		jsonData, err = migrateLegacySettings(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read user settings: %w", err)
+	}
+
+	var userProfiles configuration.UserProfiles
+	if err := json.Unmarshal(jsonData, &userProfiles); err != nil {
+		return nil, fmt.Errorf("error unmarsheling user settings %w", err)
+	}
+
+	if len(userProfiles.Profiles) == 0 {
+		// files written before named profiles hold a single profile at the top level
+		var legacySettings struct {
+			configuration.UsersSettings
+			AccessToken *authenticationmodels.GithubAccessToken      `json:"access_token"`
+			Selections  map[string]configuration.RepositorySelection `json:"selections"`
+		}
+		if err := json.Unmarshal(jsonData, &legacySettings); err != nil {
+			return nil, fmt.Errorf("error unmarsheling user settings %w", err)
+		}
+
+		//logging in to github before running setup left a file with just the token
+		if legacySettings.Profile == "" && userProfiles.AccessToken != nil {
+			return &userProfiles, nil
+		}
+
+		if legacySettings.Profile == "" {
+			return nil, errNotSetUp
+		}
+
+		userProfiles = configuration.UserProfiles{
+			DefaultProfile: DefaultProfileName,
+			Profiles:       map[string]configuration.UsersSettings{DefaultProfileName: legacySettings.UsersSettings},
+			AccessToken:    legacySettings.AccessToken,
+			Selections:     legacySettings.Selections,
+		}
+	}
+
+	return &userProfiles, nil
+}
+
+// GetAccessToken older versions kept the github token in plain text next to the profiles, this is only read to move it into the credential store
+func GetAccessToken() (*authenticationmodels.GithubAccessToken, error) {
+	userProfiles, err := GetUserProfiles()
+	if errors.Is(err, os.ErrNotExist) || errors.Is(err, errNotSetUp) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return userProfiles.AccessToken, nil
+}
+
+func RemoveAccessToken() error {
+	userProfiles, err := GetUserProfiles()
+	if errors.Is(err, os.ErrNotExist) || errors.Is(err, errNotSetUp) {
+		return nil //nothing stored so nothing to remove
+	}
+	if err != nil {
+		return err
+	}
+
+	userProfiles.AccessToken = nil
+	return SaveUserProfiles(userProfiles)
+}
+
+// migrateLegacySettings moves a settings file from the old relative path into the config directory, the old file is only removed once the new one is written
+func migrateLegacySettings(path string) ([]byte, error) {
+	jsonData, err := os.ReadFile(legacyFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeSettings(path, jsonData); err != nil {
+		return nil, err
+	}
+
+	if err := os.Remove(legacyFilePath); err != nil {
+		fmt.Printf("error removing old user settings at %s: %s", legacyFilePath, err.Error()) //log but dont fail
+	}
+
+	return jsonData, nil
+}
+
+// SaveUserProfiles writes to a temp file first so a failed write cant lose every profile
+func SaveUserProfiles(userProfiles *configuration.UserProfiles) error {
+	jsonData, err := json.Marshal(userProfiles)
 	if err != nil {
 		return fmt.Errorf("error marsheling json, %w", err)
 	}
 
-	if err := os.WriteFile(filePath, jsonData, 0644); err != nil {
-		return fmt.Errorf("error writing file at %s, %w", filePath, err)
+	path, err := settingsPath()
+	if err != nil {
+		return err
+	}
+
+	return writeSettings(path, jsonData)
+}
+
+// writeSettings only the user can read the file, selections and profiles say a lot about what they have access to
+func writeSettings(path string, jsonData []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("error creating user settings directory: %w", err)
+	}
+
+	// WriteFile only applies the mode to new files so a leftover temp file is removed first
+	tmpPath := path + ".tmp"
+	os.Remove(tmpPath)
+	if err := os.WriteFile(tmpPath, jsonData, 0600); err != nil {
+		return fmt.Errorf("error writing file at %s, %w", tmpPath, err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("error writing file at %s, %w", path, err)
 	}
 
 	return nil
 }
 
-func GetUserSettings() (*configuration.UsersSettings, error) {
-	jsonData, err := os.ReadFile(filePath)
+func GetSelection(name string) (configuration.RepositorySelection, error) {
+	userProfiles, err := GetUserProfiles()
 	if err != nil {
-		return nil, fmt.Errorf("cannot read user settings: %w", err)
+		return configuration.RepositorySelection{}, err
 	}
 
-	var userSettings configuration.UsersSettings
-	err = json.Unmarshal(jsonData, &userSettings)
+	selection, ok := userProfiles.Selections[name]
+	if !ok {
+		return configuration.RepositorySelection{}, fmt.Errorf("no saved selection called %s", name)
+	}
+
+	return selection, nil
+}
+
+// SaveSelection overwrites any selection already saved under the same name
+func SaveSelection(name string, selection configuration.RepositorySelection) error {
+	userProfiles, err := GetUserProfiles()
 	if err != nil {
-		return nil, fmt.Errorf("error unmarsheling user settings %w", err)
+		return err
 	}
 
-	if userSettings.Profile == "" {
-		return nil, fmt.Errorf("error, validating set up please insure the set up command has been ran")
+	if userProfiles.Selections == nil {
+		userProfiles.Selections = make(map[string]configuration.RepositorySelection)
 	}
+	userProfiles.Selections[name] = selection
 
-	return &userSettings, nil
+	return SaveUserProfiles(userProfiles)
 }
@@ -0,0 +1,70 @@
+package githubcommands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// deepscan runs itself as gits askpass program, these only ever live in the environment of the git process
+const (
+	askpassEnvironmentVariable  = "DEEPSCAN_ASKPASS"
+	usernameEnvironmentVariable = "DEEPSCAN_GIT_USERNAME"
+	passwordEnvironmentVariable = "DEEPSCAN_GIT_PASSWORD"
+)
+
+// Credentials for cloning over https, tokens go in the password with whatever username the provider expects
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// RunAskpass answers gits username or password prompt when deepscan was started as GIT_ASKPASS,
+// false means this is a normal run
+func RunAskpass(args []string) bool {
+	if os.Getenv(askpassEnvironmentVariable) != "1" {
+		return false
+	}
+
+	// git passes the prompt e.g. "Username for 'https://github.com': "
+	var prompt string
+	if len(args) > 1 {
+		prompt = args[1]
+	}
+
+	if strings.HasPrefix(prompt, "Username") {
+		fmt.Println(os.Getenv(usernameEnvironmentVariable))
+	} else {
+		fmt.Println(os.Getenv(passwordEnvironmentVariable))
+	}
+
+	return true
+}
+
+// gitEnvironment stops git from prompting, which would hang a concurrent clone, and hands credentials to it through askpass
+func gitEnvironment(credentials *Credentials) ([]string, error) {
+	env := append(os.Environ(),
+		"GIT_TERMINAL_PROMPT=0", // Disable terminal prompts
+		"SSH_ASKPASS=echo",      // Disable SSH prompts
+	)
+
+	if credentials == nil {
+		return append(env, "GIT_ASKPASS=echo"), nil
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("error finding deepscan to answer git credential prompts: %w", err)
+	}
+
+	return append(env,
+		"GIT_ASKPASS="+executable,
+		askpassEnvironmentVariable+"=1",
+		usernameEnvironmentVariable+"="+credentials.Username,
+		passwordEnvironmentVariable+"="+credentials.Password,
+		// clear any configured credential helpers so a stale stored login cant win over the token
+		"GIT_CONFIG_COUNT=1",
+		"GIT_CONFIG_KEY_0=credential.helper",
+		"GIT_CONFIG_VALUE_0=",
+	), nil
+}
@@ -0,0 +1,39 @@
+//go:build !windows
+
+package githubcommands
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+type fileLock struct {
+	path string
+	file *os.File
+}
+
+// lockFile takes an exclusive lock on path, when wait is false we give up straight away if someone else holds it
+func lockFile(path string, wait bool) (*fileLock, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("error opening lock file: %w", err)
+	}
+
+	how := syscall.LOCK_EX
+	if !wait {
+		how |= syscall.LOCK_NB
+	}
+
+	if err := syscall.Flock(int(file.Fd()), how); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &fileLock{path: path, file: file}, nil
+}
+
+func (l *fileLock) unlock() {
+	syscall.Flock(int(l.file.Fd()), syscall.LOCK_UN)
+	l.file.Close()
+}
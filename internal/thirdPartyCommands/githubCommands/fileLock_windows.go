@@ -0,0 +1,40 @@
+//go:build windows
+
+package githubcommands
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+type fileLock struct {
+	path string
+	file *os.File
+}
+
+// lockFile takes an exclusive lock on path, when wait is false we give up straight away if someone else holds it
+func lockFile(path string, wait bool) (*fileLock, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("error opening lock file: %w", err)
+	}
+
+	var flags uint32 = windows.LOCKFILE_EXCLUSIVE_LOCK
+	if !wait {
+		flags |= windows.LOCKFILE_FAIL_IMMEDIATELY
+	}
+
+	if err := windows.LockFileEx(windows.Handle(file.Fd()), flags, 0, 1, 0, &windows.Overlapped{}); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &fileLock{path: path, file: file}, nil
+}
+
+func (l *fileLock) unlock() {
+	windows.UnlockFileEx(windows.Handle(l.file.Fd()), 0, 1, 0, &windows.Overlapped{})
+	l.file.Close()
+}
@@ -5,37 +5,62 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
+	"sync"
 
+	"github.com/RobsonDevCode/deepscan/internal/clients/models"
+	projecttypessupported "github.com/RobsonDevCode/deepscan/internal/constants/projectTypesSupported"
 	scannerconstants "github.com/RobsonDevCode/deepscan/internal/scanner/constants"
 	"golang.org/x/sync/errgroup"
 )
 
-func CloneRepository(sshUrl string, ctx context.Context) error {
-	if err := createTempDir(); err != nil {
-		return err
+// CloneRepository checks out ref, or the default branch when its empty, into directory and returns the commit sha that was checked out
+// credentials are only needed for https urls, ssh urls use the users keys
+func CloneRepository(sshUrl string, ref string, credentials *Credentials, mirror *RepositoryMirror, directory string, ctx context.Context) (string, error) {
+	env := os.Environ()
+	if credentials != nil {
+		credentialEnv, err := gitEnvironment(credentials)
+		if err != nil {
+			return "", err
+		}
+		env = credentialEnv
 	}
 
-	cmd := exec.CommandContext(ctx, "git", "clone", sshUrl)
-	cmd.Dir = scannerconstants.TempDirctory
-	cmd.Env = os.Environ()
+	ref, err := ResolveRef(sshUrl, ref, env, ctx)
+	if err != nil {
+		return "", err
+	}
 
-	if err := cmd.Run(); err != nil {
+	sha, err := mirror.Clone(sshUrl, ref, env, directory, ctx)
+	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
-			return fmt.Errorf("timeout attempting to clone: %s", sshUrl)
+			return "", fmt.Errorf("timeout attempting to clone: %s", sshUrl)
 		}
 
-		return err
+		return "", err
 	}
 
-	return nil
+	if err := mirror.Evict(); err != nil {
+		fmt.Printf("\nerror evicting repository mirrors: %v", err)
+	}
+
+	return sha, nil
 }
 
-func CloneAll(urls []string, ctx context.Context) error {
-	if err := createTempDir(); err != nil {
-		return fmt.Errorf("\n error creating temp file: %w", err)
+// CloneAll clones each url at its ref in refs into directory, the commit shas checked out are returned keyed by repository directory.
+// a repository that fails to clone is returned as a failed scan and the rest carry on
+func CloneAll(urls []string, refs map[string]string, credentials *Credentials, mirror *RepositoryMirror, directory string, ctx context.Context) (map[string]string, []models.FailedProjectScan, error) {
+	//this is needed as this will deadlock on git's hang time if not
+	env, err := gitEnvironment(credentials)
+	if err != nil {
+		return nil, nil, err
 	}
 
+	var mu sync.Mutex
+	commits := make(map[string]string)
+	var failed []models.FailedProjectScan
+
 	g, gCtx := errgroup.WithContext(ctx)
 	for _, url := range urls {
 		g.Go(func() error {
@@ -44,31 +69,34 @@ func CloneAll(urls []string, ctx context.Context) error {
 				return fmt.Errorf("context cancelled before starting clone of %s: %w", url, gCtx.Err())
 
 			default:
-				cmd := exec.CommandContext(gCtx, "git", "clone", url)
-				cmd.Dir = scannerconstants.TempDirctory
-
-				//this is needed as this will deadlock on git's hang time if not
-				cmd.Env = append(os.Environ(),
-					"GIT_TERMINAL_PROMPT=0", // Disable terminal prompts
-					"GIT_ASKPASS=echo",      // Provide dummy askpass
-					"SSH_ASKPASS=echo",      // Disable SSH prompts
-				)
-
-				if err := cmd.Run(); err != nil {
+				sha, err := cloneAtRef(url, refs[url], env, mirror, directory, gCtx)
+				if err != nil {
 					if ctx.Err() == context.DeadlineExceeded {
-						return fmt.Errorf("timeout attempting to clone: %s", urls)
+						return fmt.Errorf("timeout attempting to clone: %s", url)
 					}
 
-					//due to how the repo is set up, it throws an auth error for concurrent access
-					//it still clones but we want to ignore auth erros for this repo
-					if strings.Contains(err.Error(), "status 128") {
-						//do nothing
-					} else {
-						return fmt.Errorf("failed to clone %s:  %w", url, err)
+					if ctx.Err() != nil {
+						return err
 					}
 
+					// a half finished clone would be scanned as if it were the repository
+					os.RemoveAll(filepath.Join(directory, RepositoryDirectory(url)))
+
+					fmt.Printf("\nfailed to clone project: %s", url)
+					mu.Lock()
+					failed = append(failed, models.FailedProjectScan{
+						Error:       fmt.Errorf("failed to clone %s: %w", url, err),
+						ServiceName: RepositoryDirectory(url),
+						ProjectName: url,
+					})
+					mu.Unlock()
+					return nil
 				}
 
+				mu.Lock()
+				commits[RepositoryDirectory(url)] = sha
+				mu.Unlock()
+
 				fmt.Printf("\nclone completed for project: %s", url)
 				return nil
 			}
@@ -77,22 +105,97 @@ func CloneAll(urls []string, ctx context.Context) error {
 	}
 
 	if err := g.Wait(); err != nil {
-		os.RemoveAll(scannerconstants.TempDirctory)
-		return err
+		return nil, nil, err
+	}
+
+	//log but dont fail, a mirror over budget still scans fine
+	if err := mirror.Evict(); err != nil {
+		fmt.Printf("\nerror evicting repository mirrors: %v", err)
+	}
+
+	if len(failed) > 0 {
+		fmt.Printf("\n Cloned %d of %d repos ", len(commits), len(urls))
+		return commits, failed, nil
 	}
 
 	fmt.Printf("\n Successfully cloned all repos ")
-	return nil
+	return commits, nil, nil
 }
 
-func createTempDir() error {
-	if _, err := os.Stat(scannerconstants.TempDirctory); err == nil {
-		os.RemoveAll(scannerconstants.TempDirctory)
+func cloneAtRef(url string, ref string, env []string, mirror *RepositoryMirror, directory string, ctx context.Context) (string, error) {
+	ref, err := ResolveRef(url, ref, env, ctx)
+	if err != nil {
+		return "", err
 	}
 
-	if err := os.Mkdir(scannerconstants.TempDirctory, 0755); err != nil {
-		return fmt.Errorf("error making temp directory: %w", err)
+	return mirror.Clone(url, ref, env, directory, ctx)
+}
+
+// cloneManifests only fetches the latest commit and checks out the manifest files, history and binaries are never downloaded.
+// older git versions and servers without partial clone support fall back to a full clone
+func cloneManifests(url string, parent string, directory string, ref string, env []string, ctx context.Context) error {
+	sparseErr := runGit(env, ctx, parent, "clone", "--depth", "1", "--filter=blob:none", "--sparse", url, directory)
+	if sparseErr == nil {
+		args := append([]string{"sparse-checkout", "set", "--no-cone"}, projecttypessupported.ManifestPatterns...)
+		sparseErr = runGit(env, ctx, filepath.Join(parent, directory), args...)
+	}
+
+	if sparseErr != nil {
+		if ctx.Err() != nil {
+			return sparseErr
+		}
+
+		fmt.Printf("\nsparse clone of %s failed, falling back to a full clone: %v", url, sparseErr)
+		if err := os.RemoveAll(filepath.Join(parent, directory)); err != nil {
+			return fmt.Errorf("error removing partial clone of %s: %w", url, err)
+		}
+
+		if err := runGit(env, ctx, parent, "clone", url, directory); err != nil {
+			return err
+		}
+	}
+
+	return checkoutRef(env, ctx, filepath.Join(parent, directory), ref)
+}
+
+func runGit(env []string, ctx context.Context, dir string, args ...string) error {
+	_, err := gitOutput(env, ctx, dir, args...)
+	return err
+}
+
+func gitOutput(env []string, ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = env
+
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w, %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// RepositoryDirectory matches the directory git clone would pick so service names stay the same
+func RepositoryDirectory(url string) string {
+	url = strings.TrimSuffix(strings.TrimSuffix(url, "/"), ".git")
+	if index := strings.LastIndexAny(url, "/:\\"); index >= 0 {
+		url = url[index+1:]
+	}
+
+	return url
+}
+
+// CreateWorkingDirectory gives each run its own directory to clone into so concurrent runs dont remove each others checkouts,
+// the caller removes it once the scan is done
+func CreateWorkingDirectory() (string, error) {
+	directory, err := os.MkdirTemp("", scannerconstants.WorkingDirectoryPattern)
+	if err != nil {
+		return "", fmt.Errorf("error making temp directory: %w", err)
 	}
 
-	return nil
+	return directory, nil
 }
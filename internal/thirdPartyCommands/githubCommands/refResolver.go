@@ -0,0 +1,94 @@
+package githubcommands
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/RobsonDevCode/deepscan/internal/versioning"
+)
+
+// LatestTag resolves to the highest semver tag, prereleases are skipped as they are rarely whats in production
+const LatestTag = "latest-tag"
+
+var semverTag = regexp.MustCompile(`^v?\d+(\.\d+)*$`)
+
+// IsRefPattern patterns need the remotes refs listed before we know what to check out
+func IsRefPattern(ref string) bool {
+	return ref == LatestTag || strings.ContainsAny(ref, "*?[")
+}
+
+// ResolveRef turns a pattern like release/* or latest-tag into a branch or tag name, anything else is already a ref
+func ResolveRef(url string, ref string, env []string, ctx context.Context) (string, error) {
+	if !IsRefPattern(ref) {
+		return ref, nil
+	}
+
+	output, err := gitOutput(env, ctx, "", "ls-remote", "--refs", "--heads", "--tags", url)
+	if err != nil {
+		return "", fmt.Errorf("error listing refs for %s: %w", url, err)
+	}
+
+	// only the part the pattern matched is compared so release/1.10 beats release/1.9
+	literalPrefix := ref
+	if index := strings.IndexAny(ref, "*?["); index >= 0 {
+		literalPrefix = ref[:index]
+	}
+
+	var resolved string
+	for _, line := range strings.Split(output, "\n") {
+		_, fullName, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+
+		var name string
+		if ref == LatestTag {
+			tag, isTag := strings.CutPrefix(fullName, "refs/tags/")
+			if !isTag || !semverTag.MatchString(tag) {
+				continue
+			}
+			name = tag
+		} else {
+			name = strings.TrimPrefix(strings.TrimPrefix(fullName, "refs/heads/"), "refs/tags/")
+			if matched, _ := path.Match(ref, name); !matched {
+				continue
+			}
+		}
+
+		if resolved == "" || versioning.Compare(strings.TrimPrefix(name, literalPrefix), strings.TrimPrefix(resolved, literalPrefix)) > 0 {
+			resolved = name
+		}
+	}
+
+	if resolved == "" {
+		return "", fmt.Errorf("no branch or tag in %s matches %s", url, ref)
+	}
+
+	fmt.Printf("\nresolved %s to %s for %s", ref, resolved, url)
+	return resolved, nil
+}
+
+// checkoutRef a shallow fetch of just the ref works for branches, tags and commit shas alike
+func checkoutRef(env []string, ctx context.Context, repositoryPath string, ref string) error {
+	if ref == "" {
+		return nil
+	}
+
+	if err := runGit(env, ctx, repositoryPath, "fetch", "--depth", "1", "origin", ref); err != nil {
+		return fmt.Errorf("error fetching %s: %w", ref, err)
+	}
+
+	return runGit(env, ctx, repositoryPath, "checkout", "--detach", "FETCH_HEAD")
+}
+
+func commitSha(env []string, ctx context.Context, repositoryPath string) (string, error) {
+	sha, err := gitOutput(env, ctx, repositoryPath, "rev-parse", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("error reading commit: %w", err)
+	}
+
+	return sha, nil
+}
@@ -0,0 +1,268 @@
+package githubcommands
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/RobsonDevCode/deepscan/internal/configuration"
+)
+
+const defaultMirrorMaxSizeMb = 2048
+
+// RepositoryMirror keeps a clone of every repository we scan so later runs only fetch what changed,
+// each mirror has a lock file next to it so concurrent deepscan runs dont fetch into the same clone
+type RepositoryMirror struct {
+	enabled   bool
+	directory string
+	maxBytes  int64
+}
+
+func NewRepositoryMirror(config *configuration.Config) (*RepositoryMirror, error) {
+	settings := config.RepositoryMirrorSettings
+	if !settings.Enabled {
+		return &RepositoryMirror{}, nil
+	}
+
+	directory := settings.Directory
+	if directory == "" {
+		cacheDirectory, err := os.UserCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("error finding user cache directory for the repository mirror: %w", err)
+		}
+		directory = filepath.Join(cacheDirectory, "deepscan", "mirrors")
+	}
+
+	maxSizeMb := settings.MaxSizeMb
+	if maxSizeMb <= 0 {
+		maxSizeMb = defaultMirrorMaxSizeMb
+	}
+
+	return &RepositoryMirror{
+		enabled:   true,
+		directory: directory,
+		maxBytes:  maxSizeMb * 1024 * 1024,
+	}, nil
+}
+
+// Clone puts the manifests for url at ref into directory, through the mirror when its enabled,
+// and returns the commit sha that was checked out
+func (m *RepositoryMirror) Clone(url string, ref string, env []string, directory string, ctx context.Context) (string, error) {
+	if m == nil || !m.enabled {
+		repositoryPath := filepath.Join(directory, RepositoryDirectory(url))
+		if err := cloneManifests(url, directory, RepositoryDirectory(url), ref, env, ctx); err != nil {
+			return "", err
+		}
+
+		return commitSha(env, ctx, repositoryPath)
+	}
+
+	if err := os.MkdirAll(m.directory, 0755); err != nil {
+		return "", fmt.Errorf("error making mirror directory: %w", err)
+	}
+
+	key := mirrorKey(url)
+	lock, err := lockFile(filepath.Join(m.directory, key+".lock"), true)
+	if err != nil {
+		return "", fmt.Errorf("error locking mirror for %s: %w", url, err)
+	}
+	defer lock.unlock()
+
+	mirrorPath := filepath.Join(m.directory, key)
+	if err := m.update(url, key, ref, env, ctx); err != nil {
+		return "", err
+	}
+
+	// the lock files modified time is what the lru eviction goes off
+	now := time.Now()
+	os.Chtimes(lock.path, now, now)
+
+	sha, err := commitSha(env, ctx, mirrorPath)
+	if err != nil {
+		return "", err
+	}
+
+	return sha, copyWorkingTree(mirrorPath, filepath.Join(directory, RepositoryDirectory(url)))
+}
+
+// update fetches ref, or the default branch, into an existing mirror, a mirror we cant fetch into is thrown away and cloned again
+func (m *RepositoryMirror) update(url string, key string, ref string, env []string, ctx context.Context) error {
+	mirrorPath := filepath.Join(m.directory, key)
+
+	fetchRef := ref
+	if fetchRef == "" {
+		fetchRef = "HEAD"
+	}
+
+	if _, err := os.Stat(filepath.Join(mirrorPath, ".git")); err == nil {
+		fetchErr := runGit(env, ctx, mirrorPath, "fetch", "--depth", "1", "origin", fetchRef)
+		if fetchErr == nil {
+			fetchErr = runGit(env, ctx, mirrorPath, "reset", "--hard", "FETCH_HEAD")
+		}
+
+		if fetchErr == nil || ctx.Err() != nil {
+			return fetchErr
+		}
+
+		fmt.Printf("\nupdating mirror of %s failed, cloning again: %v", url, fetchErr)
+	}
+
+	if err := os.RemoveAll(mirrorPath); err != nil {
+		return fmt.Errorf("error removing mirror of %s: %w", url, err)
+	}
+
+	return cloneManifests(url, m.directory, key, ref, env, ctx)
+}
+
+// Evict removes the least recently used mirrors until we are back under the disk budget,
+// mirrors another run has locked are skipped
+func (m *RepositoryMirror) Evict() error {
+	if m == nil || !m.enabled {
+		return nil
+	}
+
+	lockPaths, err := filepath.Glob(filepath.Join(m.directory, "*.lock"))
+	if err != nil {
+		return fmt.Errorf("error listing mirrors: %w", err)
+	}
+
+	type mirrorEntry struct {
+		lockPath string
+		path     string
+		size     int64
+		lastUsed time.Time
+	}
+
+	var entries []mirrorEntry
+	var total int64
+	for _, lockPath := range lockPaths {
+		info, err := os.Stat(lockPath)
+		if err != nil {
+			continue
+		}
+
+		path := strings.TrimSuffix(lockPath, ".lock")
+		size, err := directorySize(path)
+		if err != nil {
+			continue
+		}
+
+		entries = append(entries, mirrorEntry{lockPath: lockPath, path: path, size: size, lastUsed: info.ModTime()})
+		total += size
+	}
+
+	slices.SortFunc(entries, func(a, b mirrorEntry) int {
+		return a.lastUsed.Compare(b.lastUsed)
+	})
+
+	for _, entry := range entries {
+		if total <= m.maxBytes {
+			break
+		}
+
+		if entry.size == 0 {
+			continue
+		}
+
+		lock, err := lockFile(entry.lockPath, false)
+		if err != nil {
+			continue
+		}
+
+		if err := os.RemoveAll(entry.path); err != nil {
+			lock.unlock()
+			return fmt.Errorf("error evicting mirror %s: %w", entry.path, err)
+		}
+		lock.unlock()
+
+		total -= entry.size
+	}
+
+	return nil
+}
+
+// mirrorKey keeps the repository name readable and hashes the url so forks with the same name dont collide
+func mirrorKey(url string) string {
+	hash := sha256.Sum256([]byte(url))
+	return RepositoryDirectory(url) + "-" + hex.EncodeToString(hash[:6])
+}
+
+func copyWorkingTree(source string, destination string) error {
+	return filepath.WalkDir(source, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() && entry.Name() == ".git" {
+			return filepath.SkipDir
+		}
+
+		relativePath, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(destination, relativePath)
+
+		if entry.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		return copyFile(path, target)
+	})
+}
+
+func copyFile(source string, destination string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return fmt.Errorf("error opening %s: %w", source, err)
+	}
+	defer in.Close()
+
+	out, err := os.Create(destination)
+	if err != nil {
+		return fmt.Errorf("error creating %s: %w", destination, err)
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, in); err != nil {
+		return fmt.Errorf("error copying %s: %w", source, err)
+	}
+
+	return nil
+}
+
+func directorySize(path string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(path, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.Type().IsRegular() {
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+
+	return size, err
+}
@@ -0,0 +1,24 @@
+package npmmodels
+
+// PackageLock is package-lock.json read directly, used when theres no checkout for npm ls to run in
+type PackageLock struct {
+	Name            string `json:"name"`
+	Version         string `json:"version"`
+	LockfileVersion int    `json:"lockfileVersion"`
+	//lockfile v2 and v3, keyed by install path e.g. node_modules/a/node_modules/b
+	Packages map[string]PackageLockEntry `json:"packages"`
+	//lockfile v1 is already nested the same way npm ls prints it
+	Dependencies map[string]NpmPackage `json:"dependencies"`
+}
+
+type PackageLockEntry struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Link    bool   `json:"link"`
+	//workspace links point at the folder the package really lives in
+	Resolved             string            `json:"resolved"`
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+}
@@ -1,7 +1,12 @@
 package cmdmodels
 
 type Repository struct {
-	SSHUrl     string `json:"sshUrl"`
+	SSHUrl   string `json:"sshUrl"`
+	HttpsUrl string `json:"remoteUrl"`
+	//the providers api url for the repository, remote scans read the tree and manifests through it
+	ApiUrl     string `json:"apiUrl"`
 	Name       string `json:"name"`
 	IsDisabled bool   `json:"isDisabled"`
+	//topics on github, gitlab and gitea, nothing on providers without labels
+	Topics []string `json:"topics"`
 }
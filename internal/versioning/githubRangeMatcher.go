@@ -0,0 +1,45 @@
+package versioning
+
+import "strings"
+
+// InGithubRange checks a version against githubs vulnerableVersionRange format e.g. ">= 1.0.0, < 1.2.3"
+func InGithubRange(version string, versionRange string) bool {
+	if version == "" || strings.TrimSpace(versionRange) == "" {
+		return true // nothing to compare against so we report it rather than hide it
+	}
+
+	for _, condition := range strings.Split(versionRange, ",") {
+		condition = strings.TrimSpace(condition)
+
+		operator := "="
+		for _, candidate := range []string{">=", "<=", ">", "<", "="} {
+			if strings.HasPrefix(condition, candidate) {
+				operator = candidate
+				break
+			}
+		}
+
+		bound := strings.TrimSpace(strings.TrimPrefix(condition, operator))
+		result := Compare(version, bound)
+
+		var matches bool
+		switch operator {
+		case ">=":
+			matches = result >= 0
+		case "<=":
+			matches = result <= 0
+		case ">":
+			matches = result > 0
+		case "<":
+			matches = result < 0
+		default:
+			matches = result == 0
+		}
+
+		if !matches {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,33 @@
+package versioning
+
+import "testing"
+
+func TestInGithubRange(t *testing.T) {
+	tests := []struct {
+		name         string
+		version      string
+		versionRange string
+		expected     bool
+	}{
+		{name: "inside a bounded range", version: "1.1.0", versionRange: ">= 1.0.0, < 1.2.3", expected: true},
+		{name: "at the fixed version", version: "1.2.3", versionRange: ">= 1.0.0, < 1.2.3", expected: false},
+		{name: "below the introduced version", version: "0.9.0", versionRange: ">= 1.0.0, < 1.2.3", expected: false},
+		{name: "upper bound only", version: "0.0.1", versionRange: "< 2.0.0", expected: true},
+		{name: "at an inclusive upper bound", version: "2.0.0", versionRange: "<= 2.0.0", expected: true},
+		{name: "exclusive lower bound", version: "1.0.0", versionRange: "> 1.0.0", expected: false},
+		{name: "exact version", version: "3.1.4", versionRange: "= 3.1.4", expected: true},
+		{name: "bare version is exact", version: "3.1.5", versionRange: "3.1.4", expected: false},
+		{name: "prerelease of the fixed version", version: "1.2.3-beta", versionRange: ">= 1.0.0, < 1.2.3", expected: true},
+		{name: "v prefixed version", version: "v1.1.0", versionRange: ">= 1.0.0, < 1.2.3", expected: true},
+		{name: "no installed version", version: "", versionRange: "< 1.0.0", expected: true},
+		{name: "no range", version: "1.0.0", versionRange: " ", expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := InGithubRange(test.version, test.versionRange); got != test.expected {
+				t.Errorf("expected %s in %q to be %t", test.version, test.versionRange, test.expected)
+			}
+		})
+	}
+}
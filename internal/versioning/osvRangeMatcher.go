@@ -0,0 +1,130 @@
+package versioning
+
+import (
+	"slices"
+	"strings"
+
+	osvmodels "github.com/RobsonDevCode/deepscan/internal/clients/models/osv"
+)
+
+const (
+	semverRange    = "SEMVER"
+	ecosystemRange = "ECOSYSTEM"
+)
+
+// IsAffected follows the osv schema, a version is affected once it passes an introduced event
+// and stays affected until it reaches a fixed event or passes a last_affected event
+func IsAffected(version string, affected osvmodels.Affected) bool {
+	if version == "" {
+		return true // nothing to compare against so we report it rather than hide it
+	}
+
+	if slices.Contains(affected.Versions, version) {
+		return true
+	}
+
+	for _, versionRange := range affected.Ranges {
+		if versionRange.Type != semverRange && versionRange.Type != ecosystemRange {
+			continue //git ranges are commit hashes which we cant compare
+		}
+
+		if isInRange(version, versionRange.Events) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FirstPatchedVersion returns the lowest fixed version above the current version, or empty if there isnt one
+func FirstPatchedVersion(version string, affected osvmodels.Affected) string {
+	var patched string
+	for _, versionRange := range affected.Ranges {
+		for _, event := range versionRange.Events {
+			if event.Fixed == "" {
+				continue
+			}
+
+			if version != "" && Compare(event.Fixed, version) <= 0 {
+				continue
+			}
+
+			if patched == "" || Compare(event.Fixed, patched) < 0 {
+				patched = event.Fixed
+			}
+		}
+	}
+
+	return patched
+}
+
+// DescribeRanges renders osv events in the same style github uses e.g. ">= 1.0.0, < 1.2.3"
+func DescribeRanges(affected osvmodels.Affected) string {
+	var descriptions []string
+	for _, versionRange := range affected.Ranges {
+		if versionRange.Type != semverRange && versionRange.Type != ecosystemRange {
+			continue
+		}
+
+		var parts []string
+		for _, event := range sortEvents(versionRange.Events) {
+			switch {
+			case event.Introduced != "" && event.Introduced != "0":
+				parts = append(parts, ">= "+event.Introduced)
+			case event.Fixed != "":
+				parts = append(parts, "< "+event.Fixed)
+			case event.LastAffected != "":
+				parts = append(parts, "<= "+event.LastAffected)
+			}
+		}
+
+		if len(parts) > 0 {
+			descriptions = append(descriptions, strings.Join(parts, ", "))
+		}
+	}
+
+	return strings.Join(descriptions, " || ")
+}
+
+func isInRange(version string, events []osvmodels.Event) bool {
+	affected := false
+	for _, event := range sortEvents(events) {
+		switch {
+		case event.Introduced != "":
+			if event.Introduced == "0" || Compare(version, event.Introduced) >= 0 {
+				affected = true
+			}
+		case event.Fixed != "":
+			if Compare(version, event.Fixed) >= 0 {
+				affected = false
+			}
+		case event.LastAffected != "":
+			if Compare(version, event.LastAffected) > 0 {
+				affected = false
+			}
+		}
+	}
+
+	return affected
+}
+
+func sortEvents(events []osvmodels.Event) []osvmodels.Event {
+	sorted := slices.Clone(events)
+	slices.SortStableFunc(sorted, func(a, b osvmodels.Event) int {
+		return Compare(eventVersion(a), eventVersion(b))
+	})
+
+	return sorted
+}
+
+func eventVersion(event osvmodels.Event) string {
+	switch {
+	case event.Introduced != "":
+		return event.Introduced
+	case event.Fixed != "":
+		return event.Fixed
+	case event.LastAffected != "":
+		return event.LastAffected
+	}
+	return event.Limit
+}
@@ -0,0 +1,109 @@
+package versioning
+
+import (
+	"testing"
+
+	osvmodels "github.com/RobsonDevCode/deepscan/internal/clients/models/osv"
+)
+
+func semverAffected(events ...osvmodels.Event) osvmodels.Affected {
+	return osvmodels.Affected{Ranges: []osvmodels.Range{{Type: semverRange, Events: events}}}
+}
+
+func TestIsAffected(t *testing.T) {
+	twoPairs := semverAffected(
+		osvmodels.Event{Introduced: "1.0.0"}, osvmodels.Event{Fixed: "1.2.0"},
+		osvmodels.Event{Introduced: "2.0.0"}, osvmodels.Event{Fixed: "2.1.0"},
+	)
+
+	tests := []struct {
+		name     string
+		version  string
+		affected osvmodels.Affected
+		expected bool
+	}{
+		{name: "introduced 0 covers everything before the fix", version: "0.0.1", affected: semverAffected(osvmodels.Event{Introduced: "0"}, osvmodels.Event{Fixed: "1.0.0"}), expected: true},
+		{name: "introduced 0 without a fix", version: "99.0.0", affected: semverAffected(osvmodels.Event{Introduced: "0"}), expected: true},
+		{name: "at the fixed version", version: "1.0.0", affected: semverAffected(osvmodels.Event{Introduced: "0"}, osvmodels.Event{Fixed: "1.0.0"}), expected: false},
+		{name: "first pair", version: "1.1.0", affected: twoPairs, expected: true},
+		{name: "between pairs", version: "1.5.0", affected: twoPairs, expected: false},
+		{name: "second pair", version: "2.0.5", affected: twoPairs, expected: true},
+		{name: "after both pairs", version: "2.1.0", affected: twoPairs, expected: false},
+		{name: "before both pairs", version: "0.9.0", affected: twoPairs, expected: false},
+		{name: "pairs out of order", version: "2.0.5", affected: semverAffected(
+			osvmodels.Event{Introduced: "2.0.0"}, osvmodels.Event{Fixed: "2.1.0"},
+			osvmodels.Event{Introduced: "1.0.0"}, osvmodels.Event{Fixed: "1.2.0"},
+		), expected: true},
+		{name: "at last_affected", version: "1.4.0", affected: semverAffected(osvmodels.Event{Introduced: "1.0.0"}, osvmodels.Event{LastAffected: "1.4.0"}), expected: true},
+		{name: "past last_affected", version: "1.4.1", affected: semverAffected(osvmodels.Event{Introduced: "1.0.0"}, osvmodels.Event{LastAffected: "1.4.0"}), expected: false},
+		{name: "prerelease of the fixed version", version: "1.2.0-beta.1", affected: twoPairs, expected: true},
+		{name: "prerelease of the introduced version", version: "1.0.0-rc.1", affected: twoPairs, expected: false},
+		{name: "v prefixed version", version: "v2.0.5", affected: twoPairs, expected: true},
+		{name: "build metadata on the fixed version", version: "1.2.0+build.7", affected: twoPairs, expected: false},
+		{name: "listed version outside the ranges", version: "3.0.0", affected: osvmodels.Affected{Versions: []string{"3.0.0"}}, expected: true},
+		{name: "git ranges are skipped", version: "1.0.0", affected: osvmodels.Affected{Ranges: []osvmodels.Range{{Type: "GIT", Events: []osvmodels.Event{{Introduced: "0"}}}}}, expected: false},
+		{name: "ecosystem ranges are compared", version: "4.3.0", affected: osvmodels.Affected{Ranges: []osvmodels.Range{{Type: ecosystemRange, Events: []osvmodels.Event{{Introduced: "4.0.0"}, {Fixed: "4.3.1"}}}}}, expected: true},
+		{name: "no installed version", version: "", affected: twoPairs, expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := IsAffected(test.version, test.affected); got != test.expected {
+				t.Errorf("expected IsAffected(%q) to be %t", test.version, test.expected)
+			}
+		})
+	}
+}
+
+func TestFirstPatchedVersion(t *testing.T) {
+	twoPairs := semverAffected(
+		osvmodels.Event{Introduced: "1.0.0"}, osvmodels.Event{Fixed: "1.2.0"},
+		osvmodels.Event{Introduced: "2.0.0"}, osvmodels.Event{Fixed: "2.1.0"},
+	)
+
+	tests := []struct {
+		name     string
+		version  string
+		affected osvmodels.Affected
+		expected string
+	}{
+		{name: "fix for the first pair", version: "1.1.0", affected: twoPairs, expected: "1.2.0"},
+		{name: "fix for the second pair", version: "2.0.5", affected: twoPairs, expected: "2.1.0"},
+		{name: "lowest fix without a version", version: "", affected: twoPairs, expected: "1.2.0"},
+		{name: "last_affected has no fix", version: "1.1.0", affected: semverAffected(osvmodels.Event{Introduced: "1.0.0"}, osvmodels.Event{LastAffected: "1.4.0"}), expected: ""},
+		{name: "already past every fix", version: "3.0.0", affected: twoPairs, expected: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := FirstPatchedVersion(test.version, test.affected); got != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestDescribeRanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		affected osvmodels.Affected
+		expected string
+	}{
+		{name: "introduced 0 is left out", affected: semverAffected(osvmodels.Event{Introduced: "0"}, osvmodels.Event{Fixed: "1.0.0"}), expected: "< 1.0.0"},
+		{name: "last_affected is inclusive", affected: semverAffected(osvmodels.Event{Introduced: "1.0.0"}, osvmodels.Event{LastAffected: "1.4.0"}), expected: ">= 1.0.0, <= 1.4.0"},
+		{name: "events sorted", affected: semverAffected(osvmodels.Event{Fixed: "1.2.3"}, osvmodels.Event{Introduced: "1.0.0"}), expected: ">= 1.0.0, < 1.2.3"},
+		{name: "ranges joined", affected: osvmodels.Affected{Ranges: []osvmodels.Range{
+			{Type: semverRange, Events: []osvmodels.Event{{Introduced: "1.0.0"}, {Fixed: "1.2.0"}}},
+			{Type: "GIT", Events: []osvmodels.Event{{Introduced: "abc123"}}},
+			{Type: ecosystemRange, Events: []osvmodels.Event{{Introduced: "2.0.0"}, {Fixed: "2.1.0"}}},
+		}}, expected: ">= 1.0.0, < 1.2.0 || >= 2.0.0, < 2.1.0"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := DescribeRanges(test.affected); got != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, got)
+			}
+		})
+	}
+}
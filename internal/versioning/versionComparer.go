@@ -0,0 +1,93 @@
+package versioning
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Compare orders npm and nuget style versions, returning -1, 0 or 1
+func Compare(a string, b string) int {
+	aRelease, aPrerelease := splitVersion(a)
+	bRelease, bPrerelease := splitVersion(b)
+
+	aParts := strings.Split(aRelease, ".")
+	bParts := strings.Split(bRelease, ".")
+
+	for i := 0; i < max(len(aParts), len(bParts)); i++ {
+		var aPart, bPart string
+		if i < len(aParts) {
+			aPart = aParts[i]
+		}
+		if i < len(bParts) {
+			bPart = bParts[i]
+		}
+
+		if result := compareIdentifier(aPart, bPart); result != 0 {
+			return result
+		}
+	}
+
+	// a release is always newer than its prereleases e.g. 1.0.0 > 1.0.0-beta
+	switch {
+	case aPrerelease == "" && bPrerelease == "":
+		return 0
+	case aPrerelease == "":
+		return 1
+	case bPrerelease == "":
+		return -1
+	}
+
+	aIdentifiers := strings.Split(aPrerelease, ".")
+	bIdentifiers := strings.Split(bPrerelease, ".")
+	for i := 0; i < min(len(aIdentifiers), len(bIdentifiers)); i++ {
+		if result := compareIdentifier(aIdentifiers[i], bIdentifiers[i]); result != 0 {
+			return result
+		}
+	}
+
+	return compareInt(len(aIdentifiers), len(bIdentifiers))
+}
+
+func splitVersion(version string) (string, string) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+
+	if index := strings.Index(version, "+"); index >= 0 {
+		version = version[:index]
+	}
+
+	release, prerelease, _ := strings.Cut(version, "-")
+	return release, prerelease
+}
+
+func compareIdentifier(a string, b string) int {
+	aNumber, aErr := strconv.Atoi(emptyAsZero(a))
+	bNumber, bErr := strconv.Atoi(emptyAsZero(b))
+
+	switch {
+	case aErr == nil && bErr == nil:
+		return compareInt(aNumber, bNumber)
+	case aErr == nil:
+		return -1 // numeric identifiers have lower precedence than alphanumeric ones
+	case bErr == nil:
+		return 1
+	}
+
+	return strings.Compare(a, b)
+}
+
+func emptyAsZero(s string) string {
+	if s == "" {
+		return "0"
+	}
+	return s
+}
+
+func compareInt(a int, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
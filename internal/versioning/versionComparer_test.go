@@ -0,0 +1,39 @@
+package versioning
+
+import "testing"
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        string
+		b        string
+		expected int
+	}{
+		{name: "equal", a: "1.2.3", b: "1.2.3", expected: 0},
+		{name: "numeric not lexical", a: "1.10.0", b: "1.9.0", expected: 1},
+		{name: "missing parts are zero", a: "1.2", b: "1.2.0", expected: 0},
+		{name: "nuget four parts", a: "4.3.0.1", b: "4.3.0", expected: 1},
+		{name: "prerelease before release", a: "1.0.0-beta", b: "1.0.0", expected: -1},
+		{name: "release after prerelease", a: "1.0.0", b: "1.0.0-rc.1", expected: 1},
+		{name: "prerelease of a later release", a: "1.0.1-alpha", b: "1.0.0", expected: 1},
+		{name: "prerelease identifiers compare numerically", a: "1.0.0-beta.11", b: "1.0.0-beta.2", expected: 1},
+		{name: "numeric identifier below alphanumeric", a: "1.0.0-1", b: "1.0.0-alpha", expected: -1},
+		{name: "alphanumeric identifiers compare as text", a: "1.0.0-alpha", b: "1.0.0-beta", expected: -1},
+		{name: "more prerelease identifiers is newer", a: "1.0.0-alpha.1", b: "1.0.0-alpha", expected: 1},
+		{name: "v prefix ignored", a: "v1.2.3", b: "1.2.3", expected: 0},
+		{name: "build metadata ignored", a: "1.2.3+build.5", b: "1.2.3+build.9", expected: 0},
+		{name: "build metadata after a prerelease", a: "1.0.0-beta+exp.sha.5114f85", b: "1.0.0-beta", expected: 0},
+		{name: "surrounding whitespace ignored", a: " 2.0.0 ", b: "2.0.0", expected: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Compare(test.a, test.b); got != test.expected {
+				t.Errorf("expected Compare(%q, %q) to be %d, got %d", test.a, test.b, test.expected, got)
+			}
+			if got := Compare(test.b, test.a); got != -test.expected {
+				t.Errorf("expected Compare(%q, %q) to be %d, got %d", test.b, test.a, -test.expected, got)
+			}
+		})
+	}
+}
@@ -2,51 +2,165 @@ package main
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/RobsonDevCode/deepscan/cmd"
+	advisorydatabase "github.com/RobsonDevCode/deepscan/internal/advisoryDatabase"
 	cache "github.com/RobsonDevCode/deepscan/internal/caching"
+	scanresultcache "github.com/RobsonDevCode/deepscan/internal/caching/scanResultCache"
 	client "github.com/RobsonDevCode/deepscan/internal/clients"
+	azuredevopsclient "github.com/RobsonDevCode/deepscan/internal/clients/azureDevopsClient"
+	bitbucketclient "github.com/RobsonDevCode/deepscan/internal/clients/bitbucketClient"
+	giteaclient "github.com/RobsonDevCode/deepscan/internal/clients/giteaClient"
+	githubappclient "github.com/RobsonDevCode/deepscan/internal/clients/githubAppClient"
 	githubauthenticationclient "github.com/RobsonDevCode/deepscan/internal/clients/githubAuthenticationClient"
+	gitlabclient "github.com/RobsonDevCode/deepscan/internal/clients/gitlabClient"
+	osvclient "github.com/RobsonDevCode/deepscan/internal/clients/osvClient"
 	"github.com/RobsonDevCode/deepscan/internal/configuration"
+	credentialstore "github.com/RobsonDevCode/deepscan/internal/credentialStore"
 	scanner "github.com/RobsonDevCode/deepscan/internal/scanner"
+	advisorydatabaseservice "github.com/RobsonDevCode/deepscan/internal/services/advisoryDatabaseService"
+	advisorysourceservice "github.com/RobsonDevCode/deepscan/internal/services/advisorySourceService"
+	azurerepositoryservice "github.com/RobsonDevCode/deepscan/internal/services/azureRepositoryService"
+	bitbucketrepositoryservice "github.com/RobsonDevCode/deepscan/internal/services/bitbucketRepositoryService"
+	gitcredentialservice "github.com/RobsonDevCode/deepscan/internal/services/gitCredentialService"
+	gitearepositoryservice "github.com/RobsonDevCode/deepscan/internal/services/giteaRepositoryService"
 	githubrepositoryservice "github.com/RobsonDevCode/deepscan/internal/services/githubRepositoryService"
+	gitlabrepositoryservice "github.com/RobsonDevCode/deepscan/internal/services/gitlabRepositoryService"
 	gitubauthenticationservice "github.com/RobsonDevCode/deepscan/internal/services/gitubAuthenticationService"
 	packagereaderservice "github.com/RobsonDevCode/deepscan/internal/services/packageReaderService"
 	repositoryreaderservice "github.com/RobsonDevCode/deepscan/internal/services/repositoryReaderService"
+	riskscoreservice "github.com/RobsonDevCode/deepscan/internal/services/riskScoreService"
+	scandiffservice "github.com/RobsonDevCode/deepscan/internal/services/scanDiffService"
 	scanfileservice "github.com/RobsonDevCode/deepscan/internal/services/scanFileService"
+	scanremoteservice "github.com/RobsonDevCode/deepscan/internal/services/scanRemoteService"
 	scansshservice "github.com/RobsonDevCode/deepscan/internal/services/scanShhService"
 	scannerselectionservice "github.com/RobsonDevCode/deepscan/internal/services/scannerSelectionService"
-	azurecommandExcecutor "github.com/RobsonDevCode/deepscan/internal/thirdPartyCommands/azureCommands"
+	githubcommands "github.com/RobsonDevCode/deepscan/internal/thirdPartyCommands/githubCommands"
 )
 
 func main() {
+	// git runs deepscan as its askpass program, answer and exit before anything else loads
+	if githubcommands.RunAskpass(os.Args) {
+		return
+	}
 
 	cacheIntance := cache.Cache{}
-	azureCommandExcecutor := azurecommandExcecutor.NewAzureCommandExcecutor(&cacheIntance)
 	config, err := configuration.Load()
 	if err != nil {
 		fmt.Printf("error staring command line: %s", err.Error())
 		return
 	}
 
-	githubClient, err := client.NewGithubClient(config, &cacheIntance)
+	fileCredentialStore, err := credentialstore.NewFileCredentialStore(config)
+	if err != nil {
+		fmt.Printf("error staring command line: %s", err.Error())
+		return
+	}
+	credentialStore := credentialstore.NewCredentialStore(fileCredentialStore)
+
+	// a github app needs no one at a browser so it wins over the device flow, and its token replaces the pat for advisories,
+	// a token in the environment wins over both so ci can pin every github call to one token
+	_, githubTokenOverridden := credentialStore.Overridden(credentialstore.Github)
+	var githubAuthenticationService gitubauthenticationservice.GithubAuthenticatorService
+	var advisoryTokenSource client.AdvisoryTokenSource = client.PersonalAccessToken(config.GithubClientSettings.PAT)
+	if gitubauthenticationservice.IsGithubAppConfigured(config.GithubAppSettings) && !githubTokenOverridden {
+		githubAppClient, err := githubappclient.NewGithubAppClient(config, &cacheIntance)
+		if err != nil {
+			fmt.Printf("error staring command line: %s", err.Error())
+			return
+		}
+
+		githubAppAuthenticator, err := gitubauthenticationservice.NewGithubAppAuthenticator(githubAppClient, config, &cacheIntance)
+		if err != nil {
+			fmt.Printf("error staring command line: %s", err.Error())
+			return
+		}
+
+		githubAuthenticationService = githubAppAuthenticator
+		advisoryTokenSource = githubAppAuthenticator
+	}
+	advisoryTokenSource = client.NewCredentialStoreTokenSource(credentialStore, advisoryTokenSource)
+
+	githubClient, err := client.NewGithubClient(config, &cacheIntance, advisoryTokenSource)
+	if err != nil {
+		fmt.Printf("error staring command line: %s", err.Error())
+		return
+	}
+
+	// the device flow checks stored tokens against github so it comes after the client
+	if githubAuthenticationService == nil {
+		githubAuthClient, err := githubauthenticationclient.NewGithubAuthenticationClient(config, &cacheIntance)
+		if err != nil {
+			fmt.Printf("error staring command line: %s", err.Error())
+			return
+		}
+
+		githubAuthenticator := gitubauthenticationservice.NewGithubAuthenticator(githubAuthClient, githubClient, credentialStore, &cacheIntance)
+		githubAuthenticationService = &githubAuthenticator
+	}
+
+	scanResultCache, err := scanresultcache.NewScanResultCache()
+	if err != nil {
+		fmt.Printf("error staring command line: %s", err.Error())
+		return
+	}
+
+	osvClient, err := osvclient.NewOsvClient(config, &cacheIntance)
+	if err != nil {
+		fmt.Printf("error staring command line: %s", err.Error())
+		return
+	}
+
+	advisoryDatabaseStore, err := advisorydatabase.NewAdvisoryDatabaseStore()
+	if err != nil {
+		fmt.Printf("error staring command line: %s", err.Error())
+		return
+	}
+
+	githubGraphqlClient, err := client.NewGithubGraphqlClient(config, &cacheIntance, advisoryTokenSource)
 	if err != nil {
 		fmt.Printf("error staring command line: %s", err.Error())
 		return
 	}
 
+	advisorySources := advisorysourceservice.NewAdvisorySources(githubClient, githubGraphqlClient, osvClient, advisoryDatabaseStore)
 	packageReader := packagereaderservice.NewPackageReader()
-	scanner := scanner.NewScanner(githubClient, packageReader)
+	riskScorer := riskscoreservice.NewRiskScorer(config)
+	scanner := scanner.NewScanner(advisorySources, packageReader, scanResultCache, riskScorer)
+
+	repositoryService := githubrepositoryservice.NewGithubRepositoryRetrivalService(githubClient, githubAuthenticationService, config.GithubRepositoryFilters)
+	gitlabClient := gitlabclient.NewGitlabClient(config, &cacheIntance)
+	gitlabRepositoryService := gitlabrepositoryservice.NewGitlabRepositoryRetrivalService(gitlabClient)
+	bitbucketClient, err := bitbucketclient.NewBitbucketClient(config, &cacheIntance)
+	if err != nil {
+		fmt.Printf("error staring command line: %s", err.Error())
+		return
+	}
+
+	bitbucketRepositoryService := bitbucketrepositoryservice.NewBitbucketRepositoryRetrivalService(bitbucketClient)
+	azureDevopsClient := azuredevopsclient.NewAzureDevopsClient(config, &cacheIntance, credentialStore)
+	azureRepositoryService := azurerepositoryservice.NewAzureRepositoryRetrivalService(azureDevopsClient)
+	giteaClient := giteaclient.NewGiteaClient(config, &cacheIntance)
+	giteaRepositoryService := gitearepositoryservice.NewGiteaRepositoryRetrivalService(giteaClient)
+	repositoryReader := repositoryreaderservice.NewRepositoryReaderService(&azureRepositoryService, &repositoryService, &gitlabRepositoryService, &bitbucketRepositoryService, &giteaRepositoryService)
+	repositoryMirror, err := githubcommands.NewRepositoryMirror(config)
+	if err != nil {
+		fmt.Printf("error staring command line: %s", err.Error())
+		return
+	}
 
-	githubAuthClient, err := githubauthenticationclient.NewGithubAuthenticationClient(config, &cacheIntance)
-	githubAuthenticationService := gitubauthenticationservice.NewGithubAuthenticator(githubAuthClient, &cacheIntance)
-	repositoryService := githubrepositoryservice.NewGithubRepositoryRetrivalService(githubClient, &githubAuthenticationService)
-	repositoryReader := repositoryreaderservice.NewRepositoryReaderService(azureCommandExcecutor, &repositoryService)
-	sshService := scansshservice.NewSshProcessor(scanner, &repositoryReader)
+	gitCredentialService := gitcredentialservice.NewGitCredentialProvider(githubAuthenticationService, gitlabClient, azureDevopsClient, bitbucketClient, giteaClient)
+	sshService := scansshservice.NewSshProcessor(scanner, &repositoryReader, repositoryMirror, config.RepositoryRefs, gitCredentialService, config.CloneProtocol)
+	remoteService := scanremoteservice.NewRemoteProcessor(scanner, &repositoryReader, githubClient, githubAuthenticationService, gitlabClient, azureDevopsClient, config.RepositoryRefs)
 	fileService := scanfileservice.NewFileScannerService(scanner, packageReader)
-	scanSelection := scannerselectionservice.NewScanSelection(sshService, fileService, &repositoryReader)
+	scanSelection := scannerselectionservice.NewScanSelection(sshService, remoteService, fileService, &repositoryReader)
 
 	// cant DI directly into the command so we use a setter
 	cmd.SetScanSelection(scanSelection)
+	cmd.SetGithubAuthenticator(githubAuthenticationService)
+	cmd.SetGithubAuthManager(gitubauthenticationservice.NewGithubAuthManager(githubAuthenticationService, githubClient))
+	cmd.SetScanDiffService(scandiffservice.NewDiffProcessor(scanner, repositoryMirror))
+	cmd.SetAdvisoryDatabaseService(advisorydatabaseservice.NewAdvisoryDatabaseManager(advisoryDatabaseStore))
 	cmd.Execute()
 }